@@ -0,0 +1,133 @@
+package easyscan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	"github.com/popovpsk/easyscan/internal/core"
+)
+
+// BatchSender sends a pgx.Batch in one round trip.
+// *pgx.Conn, *pgxpool.Pool, *pgxpool.Conn and pgx.Tx implement it.
+type BatchSender interface {
+	SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults
+}
+
+var (
+	_ BatchSender = (*pgx.Conn)(nil)
+	_ BatchSender = (*pgxpool.Pool)(nil)
+	_ BatchSender = (*pgxpool.Conn)(nil)
+	_ BatchSender = (pgx.Tx)(nil)
+)
+
+// BatchError is returned by Batch.Send when a queued statement fails.
+type BatchError struct {
+	// Index is the position of the statement in the batch, starting from 0.
+	Index int
+	// Query is the statement as it was queued.
+	Query string
+	Err   error
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("batch statement %d %q: %v", e.Index, e.Query, e.Err)
+}
+
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
+
+// Batch queues several Get and Select calls and sends them in one round trip.
+type Batch struct {
+	batch pgx.Batch
+	items []batchItem
+}
+
+type batchItem struct {
+	query string
+	// err is an error of queueing, it's reported by Send
+	err  error
+	scan func(ctx context.Context, q Querier) error
+}
+
+// NewBatch returns an empty Batch.
+func NewBatch() *Batch {
+	return &Batch{}
+}
+
+// Get queues query, its single row is scanned into dest by Send the same way Get does.
+func (b *Batch) Get(dest interface{}, query string, args ...interface{}) {
+	b.queue(query, args, func(ctx context.Context, q Querier, opts Option) error {
+		return Get(ctx, q, dest, "", opts)
+	})
+}
+
+// Select queues query, its rows are scanned into dest by Send the same way Select does.
+func (b *Batch) Select(dest interface{}, query string, args ...interface{}) {
+	b.queue(query, args, func(ctx context.Context, q Querier, opts Option) error {
+		return Select(ctx, q, dest, "", opts)
+	})
+}
+
+// Len returns the number of queued statements.
+func (b *Batch) Len() int {
+	return len(b.items)
+}
+
+// Send sends the queued statements and scans their results in the order they were queued.
+// It stops at the first failed statement and returns a *BatchError for it.
+func (b *Batch) Send(ctx context.Context, conn BatchSender) error {
+	if conn == nil {
+		return errors.New("conn is nil")
+	}
+	conn = txFromContext(ctx, conn)
+
+	for i, item := range b.items {
+		if item.err != nil {
+			return &BatchError{Index: i, Query: item.query, Err: item.err}
+		}
+	}
+
+	br := conn.SendBatch(ctx, &b.batch)
+	q := FromBatchResults(br)
+
+	for i, item := range b.items {
+		err := item.scan(ctx, q)
+		if err != nil {
+			br.Close()
+			return &BatchError{Index: i, Query: item.query, Err: err}
+		}
+	}
+
+	err := br.Close()
+	if err != nil {
+		return fmt.Errorf("batch: %w", err)
+	}
+	return nil
+}
+
+func (b *Batch) queue(query string, args []interface{}, scan func(ctx context.Context, q Querier, opts Option) error) {
+	opts, args := core.ExtractOptions(args)
+
+	sql, args, err := expandIn(query, args, opts.EmptyIn)
+	if err != nil {
+		b.items = append(b.items, batchItem{query: query, err: err})
+		return
+	}
+	b.batch.Queue(sql, args...)
+
+	// options are applied by Get and Select when the results are scanned
+	copied := core.OptionFunc(func(o *core.Options) {
+		*o = opts
+	})
+	b.items = append(b.items, batchItem{
+		query: query,
+		scan: func(ctx context.Context, q Querier) error {
+			return scan(ctx, q, copied)
+		},
+	})
+}
@@ -0,0 +1,131 @@
+package easyscan
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// fakeBatch records the sent batch and returns the given rows one by one.
+type fakeBatch struct {
+	batch  *pgx.Batch
+	rows   []*fakeRows
+	closed bool
+}
+
+func (f *fakeBatch) SendBatch(_ context.Context, b *pgx.Batch) pgx.BatchResults {
+	f.batch = b
+	return f
+}
+
+func (f *fakeBatch) Exec() (pgconn.CommandTag, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (f *fakeBatch) Query() (pgx.Rows, error) {
+	if len(f.rows) == 0 {
+		return nil, errors.New("no more results")
+	}
+	r := f.rows[0]
+	f.rows = f.rows[1:]
+	return r, nil
+}
+
+func (f *fakeBatch) QueryRow() pgx.Row {
+	return nil
+}
+
+func (f *fakeBatch) QueryFunc([]interface{}, func(pgx.QueryFuncRow) error) (pgconn.CommandTag, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (f *fakeBatch) Close() error {
+	f.closed = true
+	return nil
+}
+
+func TestBatchScan(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("get and select", func(t *testing.T) {
+		fb := &fakeBatch{rows: []*fakeRows{
+			newFakeRows([]string{"id", "name"}, []interface{}{int64(1), "a"}, []interface{}{int64(2), "b"}),
+			newFakeRows([]string{"count"}, []interface{}{2}),
+		}}
+
+		users := []keyedUser{{ID: 9}}
+		var count int
+		b := NewBatch()
+		b.Select(&users, "SELECT * FROM users WHERE id IN ($1)", In([]int{1, 2}), WithMode(ModeReplace))
+		b.Get(&count, "SELECT count(*) FROM users")
+		equal(t, 2, b.Len())
+
+		err := b.Send(ctx, fb)
+		noError(t, err)
+		equal(t, true, fb.closed)
+		equal(t, 2, fb.batch.Len())
+		equal(t, []keyedUser{{1, "a"}, {2, "b"}}, users)
+		equal(t, 2, count)
+	})
+
+	t.Run("failed statement", func(t *testing.T) {
+		fb := &fakeBatch{rows: []*fakeRows{
+			newFakeRows([]string{"count"}, []interface{}{2}),
+			newFakeRows([]string{"count"}),
+		}}
+
+		var first, second int
+		b := NewBatch()
+		b.Get(&first, "SELECT 2")
+		b.Get(&second, "SELECT 1 WHERE false")
+
+		err := b.Send(ctx, fb)
+		var batchErr *BatchError
+		equal(t, true, errors.As(err, &batchErr))
+		equal(t, 1, batchErr.Index)
+		equal(t, "SELECT 1 WHERE false", batchErr.Query)
+		equal(t, true, errors.Is(err, pgx.ErrNoRows))
+		equal(t, true, fb.closed)
+	})
+
+	t.Run("queue error", func(t *testing.T) {
+		var result []int
+		b := NewBatch()
+		b.Select(&result, "SELECT 1")
+		b.Select(&result, "SELECT id FROM users WHERE id IN ($1)", In([]int{}))
+
+		err := b.Send(ctx, &fakeBatch{})
+		var batchErr *BatchError
+		equal(t, true, errors.As(err, &batchErr))
+		equal(t, 1, batchErr.Index)
+		equal(t, true, errors.Is(err, ErrEmptyIn))
+	})
+}
+
+func TestBatch(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	pool, err := pgxpool.Connect(ctx, connString)
+	noError(t, err)
+	defer pool.Close()
+
+	var ints []int
+	var str string
+	b := NewBatch()
+	b.Select(&ints, "SELECT generate_series(0, $1::int)", 2)
+	b.Get(&str, "SELECT 'foo'")
+	err = b.Send(ctx, pool)
+	noError(t, err)
+	equal(t, []int{0, 1, 2}, ints)
+	equal(t, "foo", str)
+
+	b = NewBatch()
+	b.Get(&str, "SELECT 'bar'")
+	b.Get(&str, "SELECT 1/0")
+	err = b.Send(ctx, pool)
+	errorContains(t, err, `batch statement 1 "SELECT 1/0"`)
+}
@@ -0,0 +1,182 @@
+package easyscan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/popovpsk/easyscan/internal/core"
+)
+
+// Order is a column of ORDER BY.
+type Order = core.Order
+
+// Asc orders by column in ascending order.
+func Asc(column string) Order {
+	return Order{Column: column}
+}
+
+// Desc orders by column in descending order.
+func Desc(column string) Order {
+	return Order{Column: column, Desc: true}
+}
+
+// WithOrderBy sets the ordering of SelectByExample.
+// Only columns tagged in the destination struct are allowed.
+func WithOrderBy(orders ...Order) Option {
+	return core.OptionFunc(func(o *core.Options) {
+		o.OrderBy = orders
+	})
+}
+
+// WithLimit sets the LIMIT of SelectByExample.
+func WithLimit(n int) Option {
+	return core.OptionFunc(func(o *core.Options) {
+		o.Limit = n
+	})
+}
+
+// WithOffset sets the OFFSET of SelectByExample.
+func WithOffset(n int) Option {
+	return core.OptionFunc(func(o *core.Options) {
+		o.Offset = n
+	})
+}
+
+// exampleOperators maps db tag options to the comparison used by SelectByExample.
+var exampleOperators = map[string]string{
+	"eq":    "=",
+	"ne":    "<>",
+	"gt":    ">",
+	"gte":   ">=",
+	"lt":    "<",
+	"lte":   "<=",
+	"like":  "LIKE",
+	"ilike": "ILIKE",
+}
+
+// SelectByExample selects the tagged columns of the dest element type from table,
+// filtered by every non-zero tagged field of filter. A field is compared with = unless its db tag
+// has one of the eq, ne, gt, gte, lt, lte, like or ilike options, e.g. `db:"name,ilike"`.
+// Non-nil pointer fields are compared with the value they point to.
+//
+// WithOrderBy, WithLimit and WithOffset page the result.
+func SelectByExample(ctx context.Context, conn Querier, dest interface{}, table string, filter interface{}, opts ...Option) error {
+	rowType, err := sliceElemStruct(dest)
+	if err != nil {
+		return err
+	}
+
+	fv := reflect.ValueOf(filter)
+	for fv.Kind() == reflect.Ptr && !fv.IsNil() {
+		fv = fv.Elem()
+	}
+	if fv.Kind() != reflect.Struct {
+		return fmt.Errorf("filter must be a struct but got %T", filter)
+	}
+
+	var o core.Options
+	for _, opt := range opts {
+		opt.Apply(&o)
+	}
+
+	b := &strings.Builder{}
+	b.WriteString("SELECT ")
+	b.WriteString(columnsOf(rowType, ""))
+	b.WriteString(" FROM ")
+	b.WriteString(quoteTable(table))
+
+	var args []interface{}
+	for _, f := range core.Fields(fv.Type()) {
+		v := f.Value(fv)
+		if v.IsZero() {
+			continue
+		}
+
+		if len(args) == 0 {
+			b.WriteString(" WHERE ")
+		} else {
+			b.WriteString(" AND ")
+		}
+		args = append(args, v.Interface())
+		b.WriteString(quoteIdentifier(f.Name))
+		b.WriteByte(' ')
+		b.WriteString(exampleOperator(f))
+		b.WriteString(" $")
+		b.WriteString(strconv.Itoa(len(args)))
+	}
+
+	if err = writeOrderBy(b, rowType, o.OrderBy); err != nil {
+		return err
+	}
+	if o.Limit > 0 {
+		args = append(args, o.Limit)
+		b.WriteString(" LIMIT $")
+		b.WriteString(strconv.Itoa(len(args)))
+	}
+	if o.Offset > 0 {
+		args = append(args, o.Offset)
+		b.WriteString(" OFFSET $")
+		b.WriteString(strconv.Itoa(len(args)))
+	}
+
+	return Select(ctx, conn, dest, b.String(), appendOptions(args, opts)...)
+}
+
+func exampleOperator(f core.Field) string {
+	for _, o := range f.Options {
+		if op, ok := exampleOperators[o]; ok {
+			return op
+		}
+	}
+	return "="
+}
+
+// writeOrderBy writes ORDER BY for the columns tagged in rowType, other columns are rejected
+// as ordering often comes from a request.
+func writeOrderBy(b *strings.Builder, rowType reflect.Type, orders []Order) error {
+	for i, o := range orders {
+		if !hasColumn(rowType, o.Column) {
+			return fmt.Errorf("order by column %q is not allowed", o.Column)
+		}
+		if i == 0 {
+			b.WriteString(" ORDER BY ")
+		} else {
+			b.WriteString(", ")
+		}
+		b.WriteString(quoteIdentifier(o.Column))
+		if o.Desc {
+			b.WriteString(" DESC")
+		}
+	}
+	return nil
+}
+
+func hasColumn(t reflect.Type, column string) bool {
+	for _, f := range core.Fields(t) {
+		if f.Name == column {
+			return true
+		}
+	}
+	return false
+}
+
+// sliceElemStruct returns the struct type of the elements of the slice pointed by dest.
+func sliceElemStruct(dest interface{}) (reflect.Type, error) {
+	t := reflect.TypeOf(dest)
+	if t == nil || t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Slice {
+		return nil, errors.New("destination must be a non nil pointer to slice")
+	}
+
+	elem := t.Elem().Elem()
+	if elem.Kind() == reflect.Ptr {
+		elem = elem.Elem()
+	}
+	if elem.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("expected a struct or a pointer to a struct in the slice but got %s", elem.Kind())
+	}
+	return elem, nil
+}
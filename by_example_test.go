@@ -0,0 +1,93 @@
+package easyscan
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type exampleUser struct {
+	ID        int64      `db:"id"`
+	Name      string     `db:"name,ilike"`
+	Age       int        `db:"age,gte"`
+	Active    *bool      `db:"active"`
+	CreatedAt *time.Time `db:"created_at,lt"`
+}
+
+func TestSelectByExampleStatement(t *testing.T) {
+	ctx := context.Background()
+	active := false
+
+	t.Run("filter", func(t *testing.T) {
+		q := &captureQuerier{rows: newFakeRows([]string{"id"}, []interface{}{int64(1)})}
+		var result []exampleUser
+		err := SelectByExample(ctx, q, &result, "users", exampleUser{Name: "%foo%", Age: 18, Active: &active},
+			WithOrderBy(Desc("age"), Asc("id")), WithLimit(10), WithOffset(20))
+		noError(t, err)
+		equal(t, `SELECT "id", "name", "age", "active", "created_at" FROM "users" WHERE "name" ILIKE $1 AND "age" >= $2 AND "active" = $3 ORDER BY "age" DESC, "id" LIMIT $4 OFFSET $5`, q.sql)
+		equal(t, []interface{}{"%foo%", 18, &active, 10, 20}, q.args)
+		equal(t, []exampleUser{{ID: 1}}, result)
+	})
+
+	t.Run("empty filter", func(t *testing.T) {
+		q := &captureQuerier{rows: newFakeRows([]string{"id"})}
+		var result []*exampleUser
+		err := SelectByExample(ctx, q, &result, "users", &exampleUser{})
+		noError(t, err)
+		equal(t, `SELECT "id", "name", "age", "active", "created_at" FROM "users"`, q.sql)
+		equal(t, 0, len(q.args))
+	})
+
+	t.Run("order by column not in allowlist", func(t *testing.T) {
+		var result []exampleUser
+		err := SelectByExample(ctx, &captureQuerier{}, &result, "users", exampleUser{}, WithOrderBy(Asc("id; DROP TABLE users")))
+		errorContains(t, err, "is not allowed")
+	})
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		var result []exampleUser
+		err := SelectByExample(ctx, &captureQuerier{}, &result, "users", 1)
+		errorContains(t, err, "filter must be a struct")
+
+		err = SelectByExample(ctx, &captureQuerier{}, result, "users", exampleUser{})
+		errorContains(t, err, "must be a non nil pointer to slice")
+
+		var ints []int
+		err = SelectByExample(ctx, &captureQuerier{}, &ints, "users", exampleUser{})
+		errorContains(t, err, "expected a struct")
+	})
+}
+
+func TestSelectByExample(t *testing.T) {
+	ctx := context.Background()
+	pool, err := pgxpool.Connect(ctx, connString)
+	noError(t, err)
+	defer pool.Close()
+
+	_, err = pool.Exec(ctx, `CREATE TABLE IF NOT EXISTS easy_scan_example(id bigserial primary key, name text, age int, active bool, created_at timestamp)`)
+	noError(t, err)
+	defer func() {
+		_, e := pool.Exec(ctx, "DROP TABLE easy_scan_example")
+		if e != nil {
+			panic(e)
+		}
+	}()
+
+	_, err = pool.Exec(ctx, `INSERT INTO easy_scan_example(name, age, active) VALUES ('Foo', 10, true), ('foobar', 20, false), ('bar', 30, true)`)
+	noError(t, err)
+
+	var result []exampleUser
+	err = SelectByExample(ctx, pool, &result, "easy_scan_example", exampleUser{Name: "foo%", Age: 10}, WithOrderBy(Desc("age")))
+	noError(t, err)
+	equal(t, 2, len(result))
+	equal(t, "foobar", result[0].Name)
+	equal(t, "Foo", result[1].Name)
+
+	active := true
+	err = SelectByExample(ctx, pool, &result, "easy_scan_example", exampleUser{Active: &active}, WithMode(ModeReplace), WithLimit(1), WithOffset(1), WithOrderBy(Asc("id")))
+	noError(t, err)
+	equal(t, 1, len(result))
+	equal(t, "bar", result[0].Name)
+}
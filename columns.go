@@ -0,0 +1,114 @@
+package easyscan
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/popovpsk/easyscan/internal/core"
+)
+
+type columnsKey struct {
+	t     reflect.Type
+	alias string
+}
+
+var columnsCache = new(sync.Map)
+
+// Columns returns the comma separated columns of the tagged struct T, e.g. `u."id", u."email"`,
+// so that a query selects exactly the columns T can hold. alias qualifies each column when not empty.
+func Columns[T any](alias string) string {
+	var zero T
+	return ColumnsOf(zero, alias)
+}
+
+// ColumnsOf is Columns for a struct value or a pointer to it.
+// It returns an empty string when v isn't a struct or has no db tags.
+func ColumnsOf(v interface{}, alias string) string {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return ""
+	}
+	return columnsOf(t, alias)
+}
+
+func columnsOf(t reflect.Type, alias string) string {
+	key := columnsKey{t: t, alias: alias}
+	cached, ok := columnsCache.Load(key)
+	if ok {
+		return cached.(string)
+	}
+
+	b := &strings.Builder{}
+	for i, f := range core.Fields(t) {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		if alias != "" {
+			b.WriteString(alias)
+			b.WriteByte('.')
+		}
+		b.WriteString(quoteIdentifier(f.Name))
+	}
+
+	result := b.String()
+	columnsCache.Store(key, result)
+	return result
+}
+
+// RenderQuery executes text as a text/template where every struct of types is available
+// by its type name, and {{columns User "u"}} renders the columns of User as Columns does:
+//
+//	RenderQuery(`SELECT {{columns User "u"}} FROM users u WHERE u.id = $1`, User{})
+func RenderQuery(text string, types ...interface{}) (string, error) {
+	funcs := template.FuncMap{
+		"columns": func(t reflect.Type, alias string) (string, error) {
+			if len(core.Fields(t)) == 0 {
+				return "", fmt.Errorf("%s has no db tags", t)
+			}
+			return columnsOf(t, alias), nil
+		},
+	}
+
+	for _, v := range types {
+		t := reflect.TypeOf(v)
+		for t != nil && t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if t == nil || t.Kind() != reflect.Struct {
+			return "", fmt.Errorf("expected a struct but got %T", v)
+		}
+		if t.Name() == "" {
+			return "", errors.New("anonymous structs can't be used in a query template")
+		}
+		funcs[t.Name()] = func() reflect.Type {
+			return t
+		}
+	}
+
+	tmpl, err := template.New("query").Funcs(funcs).Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("query template: %w", err)
+	}
+
+	b := &strings.Builder{}
+	if err = tmpl.Execute(b, nil); err != nil {
+		return "", fmt.Errorf("query template: %w", err)
+	}
+	return b.String(), nil
+}
+
+// MustRenderQuery is RenderQuery that panics on an error, it is meant for package level query variables.
+func MustRenderQuery(text string, types ...interface{}) string {
+	query, err := RenderQuery(text, types...)
+	if err != nil {
+		panic(err)
+	}
+	return query
+}
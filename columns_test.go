@@ -0,0 +1,78 @@
+package easyscan
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type columnsAudit struct {
+	CreatedAt time.Time `db:"created_at"`
+}
+
+type columnsUser struct {
+	ID    int64  `db:"id,generated"`
+	Email string `db:"email"`
+	Skip  string
+	columnsAudit
+}
+
+func TestColumns(t *testing.T) {
+	equal(t, `"id", "email", "created_at"`, Columns[columnsUser](""))
+	equal(t, `u."id", u."email", u."created_at"`, Columns[columnsUser]("u"))
+	equal(t, `u."id", u."email", u."created_at"`, Columns[*columnsUser]("u"))
+	equal(t, `"id", "email", "created_at"`, ColumnsOf(&columnsUser{}, ""))
+	equal(t, "", ColumnsOf(1, ""))
+	equal(t, "", Columns[int](""))
+}
+
+func TestRenderQuery(t *testing.T) {
+	type order struct {
+		ID     int64 `db:"id"`
+		UserID int64 `db:"user_id"`
+	}
+
+	t.Run("types", func(t *testing.T) {
+		query, err := RenderQuery(`SELECT {{columns columnsUser "u"}}, {{columns order "o"}} FROM users u JOIN orders o ON o.user_id = u.id`, columnsUser{}, &order{})
+		noError(t, err)
+		equal(t, `SELECT u."id", u."email", u."created_at", o."id", o."user_id" FROM users u JOIN orders o ON o.user_id = u.id`, query)
+	})
+
+	t.Run("unknown type", func(t *testing.T) {
+		_, err := RenderQuery(`SELECT {{columns order ""}} FROM orders`, columnsUser{})
+		errorContains(t, err, `function "order" not defined`)
+	})
+
+	t.Run("no db tags", func(t *testing.T) {
+		type empty struct{ ID int }
+		_, err := RenderQuery(`SELECT {{columns empty ""}} FROM t`, empty{})
+		errorContains(t, err, "has no db tags")
+	})
+
+	t.Run("anonymous struct", func(t *testing.T) {
+		_, err := RenderQuery(`SELECT 1`, struct{}{})
+		errorContains(t, err, "anonymous structs")
+	})
+
+	t.Run("must", func(t *testing.T) {
+		equal(t, `SELECT "id", "user_id" FROM orders`, MustRenderQuery(`SELECT {{columns order ""}} FROM orders`, order{}))
+	})
+}
+
+func TestSelectColumns(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	pool, err := pgxpool.Connect(ctx, connString)
+	noError(t, err)
+	defer pool.Close()
+
+	query := "SELECT " + Columns[columnsUser]("u") + ` FROM (SELECT 1::bigint AS id, 'foo' AS email, '2012-03-04 10:11:12'::timestamp AS created_at, 1 AS extra) u`
+
+	var result []columnsUser
+	err = Select(ctx, pool, &result, query)
+	noError(t, err)
+	equal(t, 1, len(result))
+	equal(t, "foo", result[0].Email)
+}
@@ -0,0 +1,106 @@
+package easyscan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/jackc/pgx/v4"
+
+	"github.com/popovpsk/easyscan/internal/core"
+)
+
+// CopyStructs copies rows into table with the COPY protocol and returns the number of copied rows.
+// rows must be a []T or []*T of a tagged struct, the columns come from its db tags,
+// fields tagged as generated are skipped. table may be qualified by a schema, e.g. "public.users".
+func CopyStructs(ctx context.Context, conn Copier, table string, rows interface{}) (int64, error) {
+	if conn == nil {
+		return 0, errors.New("conn is nil")
+	}
+	conn = txFromContext(ctx, conn)
+
+	src, err := newStructsSource(rows)
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := conn.CopyFrom(ctx, pgx.Identifier(strings.Split(table, ".")), src.columns, src)
+	if err != nil {
+		return n, wrapPgError(fmt.Errorf("copy from: %w", err))
+	}
+	return n, nil
+}
+
+// structsSource is a pgx.CopyFromSource over a slice of structs.
+// It reads the fields of the current element only, the slice is never copied.
+type structsSource struct {
+	slice   reflect.Value
+	isPtr   bool
+	fields  []core.Field
+	columns []string
+	values  []interface{}
+	idx     int
+	err     error
+}
+
+func newStructsSource(rows interface{}) (*structsSource, error) {
+	slice := reflect.ValueOf(rows)
+	if slice.Kind() == reflect.Ptr && !slice.IsNil() {
+		slice = slice.Elem()
+	}
+	if slice.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("expected a slice but got %T", rows)
+	}
+
+	elemType := slice.Type().Elem()
+	isPtr := elemType.Kind() == reflect.Ptr
+	if isPtr {
+		elemType = elemType.Elem()
+	}
+	if elemType.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("expected a slice of structs but got %T", rows)
+	}
+
+	s := &structsSource{slice: slice, isPtr: isPtr, idx: -1}
+	for _, f := range core.Fields(elemType) {
+		if f.HasOption(tagGenerated) {
+			continue
+		}
+		s.fields = append(s.fields, f)
+		s.columns = append(s.columns, f.Name)
+	}
+	if len(s.fields) == 0 {
+		return nil, fmt.Errorf("%s has no db tags", elemType)
+	}
+	s.values = make([]interface{}, len(s.fields))
+
+	return s, nil
+}
+
+func (s *structsSource) Next() bool {
+	s.idx++
+	return s.err == nil && s.idx < s.slice.Len()
+}
+
+// Values reuses one buffer, pgx encodes the values before it asks for the next row.
+func (s *structsSource) Values() ([]interface{}, error) {
+	elem := s.slice.Index(s.idx)
+	if s.isPtr {
+		if elem.IsNil() {
+			s.err = fmt.Errorf("element %d is nil", s.idx)
+			return nil, s.err
+		}
+		elem = elem.Elem()
+	}
+
+	for i, f := range s.fields {
+		s.values[i] = f.Value(elem).Interface()
+	}
+	return s.values, nil
+}
+
+func (s *structsSource) Err() error {
+	return s.err
+}
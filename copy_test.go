@@ -0,0 +1,109 @@
+package easyscan
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// copyRecorder drains the source like pgx does and records the values.
+type copyRecorder struct {
+	table   pgx.Identifier
+	columns []string
+	rows    [][]interface{}
+}
+
+func (c *copyRecorder) CopyFrom(_ context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error) {
+	c.table, c.columns = tableName, columnNames
+	for rowSrc.Next() {
+		values, err := rowSrc.Values()
+		if err != nil {
+			return int64(len(c.rows)), err
+		}
+		c.rows = append(c.rows, append([]interface{}(nil), values...))
+	}
+	return int64(len(c.rows)), rowSrc.Err()
+}
+
+func TestCopyStructsSource(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2012, 3, 4, 10, 11, 12, 0, time.UTC)
+
+	t.Run("values", func(t *testing.T) {
+		c := &copyRecorder{}
+		rows := []writeRow{
+			{ID: 1, Name: "foo", Age: 1, writeAudit: writeAudit{CreatedAt: now}},
+			{ID: 2, Name: "bar", Age: 2},
+		}
+		n, err := CopyStructs(ctx, c, "public.users", rows)
+		noError(t, err)
+		equal(t, int64(2), n)
+		equal(t, pgx.Identifier{"public", "users"}, c.table)
+		equal(t, []string{"name", "age", "created_at"}, c.columns)
+		equal(t, [][]interface{}{{"foo", 1, now}, {"bar", 2, time.Time{}}}, c.rows)
+	})
+
+	t.Run("pointers", func(t *testing.T) {
+		c := &copyRecorder{}
+		n, err := CopyStructs(ctx, c, "users", []*writeRow{{Name: "foo"}})
+		noError(t, err)
+		equal(t, int64(1), n)
+		equal(t, [][]interface{}{{"foo", 0, time.Time{}}}, c.rows)
+	})
+
+	t.Run("nil element", func(t *testing.T) {
+		_, err := CopyStructs(ctx, &copyRecorder{}, "users", []*writeRow{{Name: "foo"}, nil})
+		errorContains(t, err, "element 1 is nil")
+	})
+
+	t.Run("not a slice of structs", func(t *testing.T) {
+		_, err := CopyStructs(ctx, &copyRecorder{}, "users", writeRow{})
+		errorContains(t, err, "expected a slice")
+
+		_, err = CopyStructs(ctx, &copyRecorder{}, "users", []int{1})
+		errorContains(t, err, "expected a slice of structs")
+	})
+}
+
+func TestCopyStructs(t *testing.T) {
+	ctx := context.Background()
+	pool, err := pgxpool.Connect(ctx, connString)
+	noError(t, err)
+	defer pool.Close()
+
+	const createTable = `
+    CREATE TABLE if not exists easy_scan_copy(
+    id bigserial primary key,
+    name text,
+    age int,
+    created_at timestamp)`
+
+	_, err = pool.Exec(ctx, createTable)
+	noError(t, err)
+	defer func() {
+		_, e := pool.Exec(ctx, "DROP TABLE easy_scan_copy")
+		if e != nil {
+			panic(e)
+		}
+	}()
+
+	now := time.Now().Round(time.Minute).UTC()
+	rows := make([]*writeRow, 100)
+	for i := range rows {
+		rows[i] = &writeRow{Name: "foo", Age: i, writeAudit: writeAudit{CreatedAt: now}}
+	}
+
+	n, err := CopyStructs(ctx, pool, "easy_scan_copy", rows)
+	noError(t, err)
+	equal(t, int64(100), n)
+
+	var result []writeRow
+	err = Select(ctx, pool, &result, "SELECT * FROM easy_scan_copy ORDER BY id")
+	noError(t, err)
+	equal(t, 100, len(result))
+	equal(t, 99, result[99].Age)
+	equal(t, now, result[99].CreatedAt)
+}
@@ -0,0 +1,150 @@
+package easyscan
+
+import (
+	"database/sql"
+	"encoding"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+)
+
+// CSVSource is a RowSource that reads records of a csv.Reader.
+// The first record is the header with column names.
+//
+// Fields are converted from strings into destinations that implement sql.Scanner
+// or encoding.TextUnmarshaler, or have a string, bool, integer, float or []byte kind.
+// An empty field into a pointer destination is scanned as NULL.
+type CSVSource struct {
+	r       *csv.Reader
+	columns []string
+	record  []string
+	err     error
+}
+
+var _ RowSource = (*CSVSource)(nil)
+
+// NewCSVSource reads the header from r.
+func NewCSVSource(r *csv.Reader) (*CSVSource, error) {
+	header, err := r.Read()
+	if err != nil {
+		return nil, fmt.Errorf("csv header: %w", err)
+	}
+
+	return &CSVSource{r: r, columns: header}, nil
+}
+
+func (s *CSVSource) Next() bool {
+	if s.err != nil {
+		return false
+	}
+
+	record, err := s.r.Read()
+	if err != nil {
+		if err != io.EOF {
+			s.err = err
+		}
+		s.record = nil
+		return false
+	}
+
+	s.record = record
+	return true
+}
+
+func (s *CSVSource) Columns() []string {
+	return s.columns
+}
+
+func (s *CSVSource) Scan(dest ...interface{}) error {
+	if s.record == nil {
+		return fmt.Errorf("csv: scan called without a row")
+	}
+	if len(dest) != len(s.record) {
+		return fmt.Errorf("csv: got %d destinations for %d fields", len(dest), len(s.record))
+	}
+
+	for i, d := range dest {
+		if d == nil {
+			continue
+		}
+		if err := assignString(d, s.record[i]); err != nil {
+			return fmt.Errorf("csv: can't scan column %s into dest[%d]: %w", s.columns[i], i, err)
+		}
+	}
+	return nil
+}
+
+func (s *CSVSource) Err() error {
+	return s.err
+}
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+func assignString(dest interface{}, str string) error {
+	if scanner, ok := dest.(sql.Scanner); ok {
+		return scanner.Scan(str)
+	}
+
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return fmt.Errorf("destination must be a non nil pointer, got %T", dest)
+	}
+
+	return assignValue(v.Elem(), str)
+}
+
+func assignValue(v reflect.Value, str string) error {
+	if v.Kind() == reflect.Ptr {
+		if str == "" {
+			v.Set(reflect.Zero(v.Type()))
+			return nil
+		}
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return assignString(v.Interface(), str)
+	}
+
+	if v.Addr().Type().Implements(textUnmarshalerType) {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(str))
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(str)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(str)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(str, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(str, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(str, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.Uint8 {
+			return fmt.Errorf("unsupported type %s", v.Type())
+		}
+		v.SetBytes([]byte(str))
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package easyscan
+
+import (
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCSVSource(t *testing.T) {
+	type audit struct {
+		CreatedAt time.Time `db:"created_at"`
+	}
+	type user struct {
+		ID     int64    `db:"id"`
+		Name   string   `db:"name"`
+		Active bool     `db:"active"`
+		Score  *float64 `db:"score"`
+		audit
+	}
+
+	const data = `id,name,active,score,created_at,comment
+1,foo,true,1.5,2012-03-04T10:11:12Z,first
+2,bar,false,,2012-03-04T10:11:13Z,second
+`
+
+	t.Run("scan all", func(t *testing.T) {
+		src, err := NewCSVSource(csv.NewReader(strings.NewReader(data)))
+		noError(t, err)
+
+		var result []user
+		err = ScanAll(src, &result)
+		noError(t, err)
+
+		score := 1.5
+		equal(t, []user{
+			{ID: 1, Name: "foo", Active: true, Score: &score, audit: audit{CreatedAt: time.Date(2012, 3, 4, 10, 11, 12, 0, time.UTC)}},
+			{ID: 2, Name: "bar", audit: audit{CreatedAt: time.Date(2012, 3, 4, 10, 11, 13, 0, time.UTC)}},
+		}, result)
+	})
+
+	t.Run("scan one", func(t *testing.T) {
+		src, err := NewCSVSource(csv.NewReader(strings.NewReader("id\n42\n")))
+		noError(t, err)
+
+		var result int
+		err = ScanOne(src, &result)
+		noError(t, err)
+		equal(t, 42, result)
+	})
+
+	t.Run("more than one row", func(t *testing.T) {
+		src, err := NewCSVSource(csv.NewReader(strings.NewReader(data)))
+		noError(t, err)
+
+		var result user
+		equal(t, ErrMoreThanOneRow, ScanOne(src, &result))
+	})
+
+	t.Run("invalid value", func(t *testing.T) {
+		src, err := NewCSVSource(csv.NewReader(strings.NewReader("id\nfoo\n")))
+		noError(t, err)
+
+		var result []user
+		err = ScanAll(src, &result)
+		errorContains(t, err, "can't scan column id")
+	})
+
+	t.Run("reader error", func(t *testing.T) {
+		src, err := NewCSVSource(csv.NewReader(strings.NewReader("id,name\n1\n")))
+		noError(t, err)
+
+		var result []user
+		err = ScanAll(src, &result)
+		errorContains(t, err, "wrong number of fields")
+	})
+
+	t.Run("no header", func(t *testing.T) {
+		_, err := NewCSVSource(csv.NewReader(strings.NewReader("")))
+		errorContains(t, err, "csv header")
+	})
+}
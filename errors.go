@@ -0,0 +1,76 @@
+package easyscan
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgconn"
+
+	"github.com/popovpsk/easyscan/internal/core"
+)
+
+// ScanError is a failure to scan a column, it names the column, its database type,
+// the destination field and the position of the row.
+type ScanError = core.ScanError
+
+// ColumnType is the database type of a result column.
+type ColumnType = core.ColumnType
+
+// Constraint violations reported by Postgres, matched by a *ConstraintError with errors.Is.
+var (
+	ErrUniqueViolation     = errors.New("unique violation")
+	ErrForeignKeyViolation = errors.New("foreign key violation")
+	ErrCheckViolation      = errors.New("check violation")
+	ErrNotNullViolation    = errors.New("not null violation")
+)
+
+var constraintErrors = map[string]error{
+	"23505": ErrUniqueViolation,
+	"23503": ErrForeignKeyViolation,
+	"23514": ErrCheckViolation,
+	"23502": ErrNotNullViolation,
+}
+
+// ConstraintError is a constraint violation returned by Get, Select and the statement helpers.
+// It unwraps to the original error, so errors.As still finds the *pgconn.PgError.
+type ConstraintError struct {
+	// Kind is one of ErrUniqueViolation, ErrForeignKeyViolation, ErrCheckViolation and ErrNotNullViolation.
+	Kind       error
+	Constraint string
+	Table      string
+	Column     string
+	Err        error
+}
+
+func (e *ConstraintError) Error() string {
+	return fmt.Sprintf("%v: %v", e.Kind, e.Err)
+}
+
+func (e *ConstraintError) Is(target error) bool {
+	return target == e.Kind
+}
+
+func (e *ConstraintError) Unwrap() error {
+	return e.Err
+}
+
+// wrapPgError wraps err into a *ConstraintError when it is a constraint violation, otherwise returns it as is.
+func wrapPgError(err error) error {
+	var pgErr *pgconn.PgError
+	if err == nil || !errors.As(err, &pgErr) {
+		return err
+	}
+
+	kind, ok := constraintErrors[pgErr.Code]
+	if !ok {
+		return err
+	}
+
+	return &ConstraintError{
+		Kind:       kind,
+		Constraint: pgErr.ConstraintName,
+		Table:      pgErr.TableName,
+		Column:     pgErr.ColumnName,
+		Err:        err,
+	}
+}
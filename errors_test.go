@@ -0,0 +1,108 @@
+package easyscan
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+func TestConstraintErrorWrap(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("kinds", func(t *testing.T) {
+		for code, kind := range map[string]error{
+			"23505": ErrUniqueViolation,
+			"23503": ErrForeignKeyViolation,
+			"23514": ErrCheckViolation,
+			"23502": ErrNotNullViolation,
+		} {
+			err := wrapPgError(&pgconn.PgError{Code: code})
+			equal(t, true, errors.Is(err, kind))
+		}
+	})
+
+	t.Run("get", func(t *testing.T) {
+		pgErr := &pgconn.PgError{Code: "23505", ConstraintName: "users_email_key", TableName: "users", ColumnName: "email"}
+		q := QuerierFunc(func(context.Context, string, ...interface{}) (pgx.Rows, error) {
+			return nil, pgErr
+		})
+
+		var id int64
+		err := Get(ctx, q, &id, "INSERT INTO users(email) VALUES ($1) RETURNING id", "a@b.c")
+		equal(t, true, errors.Is(err, ErrUniqueViolation))
+		equal(t, false, errors.Is(err, ErrCheckViolation))
+
+		var ce *ConstraintError
+		equal(t, true, errors.As(err, &ce))
+		equal(t, "users_email_key", ce.Constraint)
+		equal(t, "users", ce.Table)
+		equal(t, "email", ce.Column)
+
+		var unwrapped *pgconn.PgError
+		equal(t, true, errors.As(err, &unwrapped))
+		equal(t, pgErr, unwrapped)
+	})
+
+	t.Run("other errors", func(t *testing.T) {
+		other := &pgconn.PgError{Code: "42P01"}
+		equal(t, error(other), wrapPgError(other))
+
+		plain := errors.New("plain")
+		equal(t, plain, wrapPgError(plain))
+		equal(t, nil, wrapPgError(nil))
+	})
+}
+
+func TestConstraintError(t *testing.T) {
+	ctx := context.Background()
+	pool, err := pgxpool.Connect(ctx, connString)
+	noError(t, err)
+	defer pool.Close()
+
+	_, err = pool.Exec(ctx, `CREATE TABLE IF NOT EXISTS easy_scan_errors(id int primary key, n int not null check (n > 0))`)
+	noError(t, err)
+	defer func() {
+		_, e := pool.Exec(ctx, "DROP TABLE easy_scan_errors")
+		if e != nil {
+			panic(e)
+		}
+	}()
+
+	var id int
+	err = Get(ctx, pool, &id, "INSERT INTO easy_scan_errors(id, n) VALUES (1, 1) RETURNING id")
+	noError(t, err)
+
+	err = Get(ctx, pool, &id, "INSERT INTO easy_scan_errors(id, n) VALUES (1, 1) RETURNING id")
+	equal(t, true, errors.Is(err, ErrUniqueViolation))
+
+	var ce *ConstraintError
+	equal(t, true, errors.As(err, &ce))
+	equal(t, "easy_scan_errors_pkey", ce.Constraint)
+	equal(t, "easy_scan_errors", ce.Table)
+
+	_, err = ExecNamed(ctx, pool, "INSERT INTO easy_scan_errors(id, n) VALUES (:id, :n)", map[string]interface{}{"id": 2, "n": 0})
+	equal(t, true, errors.Is(err, ErrCheckViolation))
+
+	_, err = ExecNamed(ctx, pool, "INSERT INTO easy_scan_errors(id, n) VALUES (:id, :n)", map[string]interface{}{"id": 2, "n": nil})
+	equal(t, true, errors.Is(err, ErrNotNullViolation))
+	equal(t, true, errors.As(err, &ce))
+	equal(t, "n", ce.Column)
+}
+
+func TestScanErrorPgx(t *testing.T) {
+	type user struct {
+		ID   int64 `db:"id"`
+		Name int   `db:"name"`
+	}
+
+	var result []user
+	err := Select(context.Background(), fakeQuerier(newFakeRows([]string{"id", "name"}, []interface{}{int64(1), "foo"})), &result, "")
+	var se *ScanError
+	equal(t, true, errors.As(err, &se))
+	equal(t, "name", se.Column)
+	equal(t, "user.Name", se.Field)
+}
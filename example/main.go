@@ -56,7 +56,7 @@ func main() {
 	defer client.Close()
 
 	result := make([]sqlImplementationInfo, 0)
-	const selectQuery = "SELECT * FROM information_schema.sql_implementation_info"
+	selectQuery := "SELECT " + easyscan.Columns[sqlImplementationInfo]("") + " FROM information_schema.sql_implementation_info"
 	err = client.Select(ctx, &result, selectQuery)
 	if err != nil {
 		panic(err)
@@ -68,7 +68,9 @@ func main() {
 	}
 
 	var getResult sqlImplementationInfo
-	const getQuery = "SELECT * FROM information_schema.sql_implementation_info WHERE implementation_info_id = $1"
+	getQuery := easyscan.MustRenderQuery(`SELECT {{columns sqlImplementationInfo "i"}}
+		FROM information_schema.sql_implementation_info i
+		WHERE i.implementation_info_id = $1`, sqlImplementationInfo{})
 	err = client.Get(ctx, &getResult, getQuery, "26")
 	if err != nil {
 		panic(err)
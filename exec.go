@@ -0,0 +1,78 @@
+package easyscan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+
+	"github.com/popovpsk/easyscan/internal/core"
+)
+
+var bytesType = reflect.TypeOf([]byte(nil))
+
+// ExecReturning executes a statement with a RETURNING clause, scans the returned rows into dest
+// and returns the command tag.
+//
+// When dest points to a slice, other than []byte, every returned row is added to it as Select does.
+// Otherwise the statement must return exactly one row, as for Get,
+// and pgx.ErrNoRows or ErrMoreThanOneRow are returned with the command tag of the executed statement.
+func ExecReturning(ctx context.Context, conn Querier, dest interface{}, query string, args ...interface{}) (pgconn.CommandTag, error) {
+	if conn == nil {
+		return nil, errors.New("conn is nil")
+	}
+	conn = txFromContext(ctx, conn)
+
+	scan, err := returningScanner(dest)
+	if err != nil {
+		return nil, err
+	}
+
+	opts, args := core.ExtractOptions(args)
+
+	query, args, err = expandIn(query, args, opts.EmptyIn)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, wrapPgError(fmt.Errorf("query: %w", err))
+	}
+	defer rows.Close()
+
+	err = scan(rowsAdapter{rows}, opts)
+
+	// the command tag is available after the rows are closed
+	rows.Close()
+	if err == nil {
+		err = rows.Err()
+	}
+	return rows.CommandTag(), wrapPgError(err)
+}
+
+func returningScanner(dest interface{}) (func(core.Rows, core.Options) error, error) {
+	t := reflect.TypeOf(dest)
+	if t != nil && t.Kind() == reflect.Ptr && t.Elem().Kind() == reflect.Slice && t.Elem() != bytesType {
+		slice, err := core.NewSlice(dest)
+		if err != nil {
+			return nil, err
+		}
+		return slice.Scan, nil
+	}
+
+	row, err := core.NewRow(dest)
+	if err != nil {
+		return nil, err
+	}
+	return func(rows core.Rows, opts core.Options) error {
+		err := row.Scan(rows, opts)
+		if err == core.ErrNoRows {
+			return pgx.ErrNoRows
+		}
+		return err
+	}, nil
+}
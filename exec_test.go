@@ -0,0 +1,93 @@
+package easyscan
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+func TestExecReturningRows(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("single", func(t *testing.T) {
+		rows := newFakeRows([]string{"id"}, []interface{}{1})
+		var id int
+		tag, err := ExecReturning(ctx, fakeQuerier(rows), &id, "INSERT ... RETURNING id")
+		noError(t, err)
+		equal(t, 1, id)
+		equal(t, int64(1), tag.RowsAffected())
+		equal(t, true, rows.closed)
+	})
+
+	t.Run("slice", func(t *testing.T) {
+		var ids []int
+		tag, err := ExecReturning(ctx, fakeQuerier(newFakeRows([]string{"id"}, []interface{}{1}, []interface{}{2})), &ids, "")
+		noError(t, err)
+		equal(t, []int{1, 2}, ids)
+		equal(t, int64(2), tag.RowsAffected())
+	})
+
+	t.Run("bytes", func(t *testing.T) {
+		var payload []byte
+		_, err := ExecReturning(ctx, fakeQuerier(newFakeRows([]string{"payload"}, []interface{}{[]byte("foo")})), &payload, "")
+		noError(t, err)
+		equal(t, []byte("foo"), payload)
+	})
+
+	t.Run("no rows", func(t *testing.T) {
+		var id int
+		tag, err := ExecReturning(ctx, fakeQuerier(newFakeRows([]string{"id"})), &id, "")
+		equal(t, pgx.ErrNoRows, err)
+		equal(t, int64(0), tag.RowsAffected())
+	})
+
+	t.Run("more than one row", func(t *testing.T) {
+		var id int
+		tag, err := ExecReturning(ctx, fakeQuerier(newFakeRows([]string{"id"}, []interface{}{1}, []interface{}{2})), &id, "")
+		equal(t, ErrMoreThanOneRow, err)
+		equal(t, int64(2), tag.RowsAffected())
+	})
+
+	t.Run("invalid destination", func(t *testing.T) {
+		_, err := ExecReturning(ctx, fakeQuerier(newFakeRows(nil)), 1, "")
+		errorContains(t, err, "must be a non nil pointer")
+	})
+}
+
+func TestExecReturning(t *testing.T) {
+	ctx := context.Background()
+	pool, err := pgxpool.Connect(ctx, connString)
+	noError(t, err)
+	defer pool.Close()
+
+	_, err = pool.Exec(ctx, "CREATE TABLE IF NOT EXISTS easy_scan_exec(id bigserial primary key, name text)")
+	noError(t, err)
+	defer func() {
+		_, e := pool.Exec(ctx, "DROP TABLE easy_scan_exec")
+		if e != nil {
+			panic(e)
+		}
+	}()
+
+	var id int64
+	tag, err := ExecReturning(ctx, pool, &id, "INSERT INTO easy_scan_exec(name) VALUES ($1) RETURNING id", "foo")
+	noError(t, err)
+	equal(t, int64(1), id)
+	equal(t, true, tag.Insert())
+
+	type row struct {
+		ID   int64  `db:"id"`
+		Name string `db:"name"`
+	}
+	var rows []row
+	tag, err = ExecReturning(ctx, pool, &rows, "INSERT INTO easy_scan_exec(name) VALUES ('bar'), ('baz') RETURNING *")
+	noError(t, err)
+	equal(t, []row{{ID: 2, Name: "bar"}, {ID: 3, Name: "baz"}}, rows)
+	equal(t, int64(2), tag.RowsAffected())
+
+	tag, err = ExecReturning(ctx, pool, &id, "DELETE FROM easy_scan_exec WHERE id = 100 RETURNING id")
+	equal(t, pgx.ErrNoRows, err)
+	equal(t, true, tag.Delete())
+}
@@ -4,57 +4,44 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"reflect"
 
 	"github.com/jackc/pgx/v4"
+
+	"github.com/popovpsk/easyscan/internal/core"
 )
 
-var ErrMoreThanOneRow = errors.New("get expects 1 row")
+var ErrMoreThanOneRow = core.ErrMoreThanOneRow
 
-func Get(ctx context.Context, conn pgxExecutor, dest interface{}, query string, args ...interface{}) error {
+func Get(ctx context.Context, conn Querier, dest interface{}, query string, args ...interface{}) error {
 	if conn == nil {
 		return errors.New("conn is nil")
 	}
+	conn = txFromContext(ctx, conn)
 
-	objectPtr := reflect.ValueOf(dest)
-	if objectPtr.Kind() != reflect.Ptr || objectPtr.IsNil() {
-		return errors.New("destination must be a non nil pointer")
-	}
-
-	objectType := objectPtr.Type().Elem()
-
-	isPgxSupported := isPgxSupportedType(objectType, true)
-	if objectType.Kind() != reflect.Struct && !isPgxSupported {
-		return fmt.Errorf("expected a struct or a pgx supported type but got %s", objectType.Kind())
-	}
-
-	rows, err := conn.Query(ctx, query, args...)
+	row, err := core.NewRow(dest)
 	if err != nil {
-		return fmt.Errorf("query: %w", err)
+		return err
 	}
-	defer rows.Close()
 
-	if !rows.Next() {
-		return pgx.ErrNoRows
-	}
-
-	if isPgxSupported {
-		err = rows.Scan(dest)
-	} else {
-		scans, e := getSliceForScan(objectType, rows.FieldDescriptions(), objectPtr)
-		if e != nil {
-			return e
-		}
-		err = rows.Scan(scans...)
-	}
+	opts, args := core.ExtractOptions(args)
 
+	query, args, err = expandIn(query, args, opts.EmptyIn)
 	if err != nil {
-		return fmt.Errorf("pgx.rows.Scan: %w", err)
+		return err
 	}
 
-	if rows.Next() {
-		return ErrMoreThanOneRow
-	}
+	err = retry(ctx, retryPolicy(conn, opts), dest, func() error {
+		rows, err := conn.Query(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("query: %w", err)
+		}
+		defer rows.Close()
 
-	return rows.Err()
+		err = row.Scan(rowsAdapter{rows}, opts)
+		if err == core.ErrNoRows {
+			return pgx.ErrNoRows
+		}
+		return err
+	})
+	return wrapPgError(err)
 }
@@ -295,3 +295,74 @@ func TestGetErrors(t *testing.T) {
 		equal(t, ErrMoreThanOneRow, easyscanErr)
 	})
 }
+
+func TestGetTypeCheck(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	pool, err := pgxpool.Connect(ctx, connString)
+	noError(t, err)
+	defer pool.Close()
+
+	var result struct {
+		Amount int32  `db:"amount"`
+		At     string `db:"at"`
+		ID     int64  `db:"id"`
+	}
+	const query = "SELECT 1.5::numeric AS amount, now() AS at, 1::int8 AS id"
+
+	err = Get(ctx, pool, &result, query, WithTypeCheck())
+	var tme *TypeMismatchError
+	equal(t, true, errors.As(err, &tme))
+	equal(t, 2, len(tme.Mismatches))
+	equal(t, "amount", tme.Mismatches[0].Column)
+	equal(t, "at", tme.Mismatches[1].Column)
+}
+
+func TestGetNullZero(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	pool, err := pgxpool.Connect(ctx, connString)
+	noError(t, err)
+	defer pool.Close()
+
+	var result struct {
+		Nickname string    `db:"nickname,nullzero"`
+		Age      int       `db:"age"`
+		Seen     time.Time `db:"seen"`
+	}
+	err = Get(ctx, pool, &result, "SELECT NULL::text AS nickname, 1 AS age, NULL::timestamp AS seen", WithNullZero())
+	noError(t, err)
+	equal(t, "", result.Nickname)
+	equal(t, 1, result.Age)
+	equal(t, true, result.Seen.IsZero())
+
+	var sum int
+	err = Get(ctx, pool, &sum, "SELECT sum(x) FROM generate_series(1, 0) AS x", WithNullZero())
+	noError(t, err)
+	equal(t, 0, sum)
+}
+
+func TestGetJSON(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	pool, err := pgxpool.Connect(ctx, connString)
+	noError(t, err)
+	defer pool.Close()
+
+	type settings struct {
+		Theme string `json:"theme"`
+	}
+	var result struct {
+		Settings settings               `db:"settings,json"`
+		Extra    map[string]interface{} `db:"extra,json"`
+		Tags     []string               `db:"tags,json"`
+	}
+	err = Get(ctx, pool, &result, `SELECT '{"theme":"dark"}'::jsonb AS settings, '{"a":1}'::json AS extra, NULL::jsonb AS tags`)
+	noError(t, err)
+	equal(t, "dark", result.Settings.Theme)
+	equal(t, map[string]interface{}{"a": float64(1)}, result.Extra)
+	equal(t, []string(nil), result.Tags)
+
+	err = Get(ctx, pool, &result, `SELECT 'dark' AS settings`)
+	errorContains(t, err, `column "settings" of type text isn't json`)
+}
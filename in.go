@@ -0,0 +1,250 @@
+package easyscan
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/popovpsk/easyscan/internal/core"
+)
+
+// ErrEmptyIn is returned when an In argument has no elements and WithEmptyIn(EmptyInFalse) isn't set.
+var ErrEmptyIn = errors.New("In argument is empty")
+
+// EmptyIn defines how an In argument without elements is expanded.
+type EmptyIn = core.EmptyIn
+
+const (
+	// EmptyInError makes the call fail with ErrEmptyIn. This is the default.
+	EmptyInError = core.EmptyInError
+	// EmptyInFalse rewrites "x IN ($1)" into "x = ANY($1)" and "x NOT IN ($1)" into "x <> ALL($1)"
+	// with an empty array, so that the first one is false and the second one is true.
+	EmptyInFalse = core.EmptyInFalse
+)
+
+// WithEmptyIn sets how an In argument without elements is expanded.
+func WithEmptyIn(e EmptyIn) Option {
+	return core.OptionFunc(func(o *core.Options) {
+		o.EmptyIn = e
+	})
+}
+
+// InValues is a slice argument that Get, Select and ExecReturning expand into one placeholder per element.
+type InValues = core.InValues
+
+// In marks a slice or an array to be expanded, e.g. with In([]int{1, 2, 3}) as $2
+// "WHERE id IN ($2) AND status = $3" is sent as "WHERE id IN ($2, $3, $4) AND status = $5".
+func In(values interface{}) InValues {
+	return InValues{Values: values}
+}
+
+// placeholder is a $N found in a query.
+type placeholder struct {
+	start, end int
+	// k is the index of the argument, N-1
+	k int
+	// array is set for $N written inside ANY(...), ALL(...) or SOME(...)
+	array bool
+	// listStart and listEnd cover "IN ($N)" or "NOT IN ($N)" when $N is the only element of the list,
+	// listEnd is 0 otherwise
+	listStart, listEnd int
+	not                bool
+}
+
+// expandIn rewrites the placeholders of In arguments and renumbers the following ones.
+// An In argument inside ANY(...), ALL(...) or SOME(...) is sent as a single array parameter.
+// query and args are returned as is when args contain no In arguments.
+func expandIn(query string, args []interface{}, emptyIn EmptyIn) (string, []interface{}, error) {
+	hasIn := false
+	for _, a := range args {
+		if _, ok := a.(InValues); ok {
+			hasIn = true
+			break
+		}
+	}
+	if !hasIn {
+		return query, args, nil
+	}
+
+	phs, err := findPlaceholders(query, len(args))
+	if err != nil {
+		return "", nil, fmt.Errorf("expand In: %w", err)
+	}
+
+	asList := make([]bool, len(args))
+	asArray := make([]bool, len(args))
+	for _, ph := range phs {
+		if ph.array {
+			asArray[ph.k] = true
+		} else {
+			asList[ph.k] = true
+		}
+	}
+
+	// starts[k] is the new position of the first value of $k+1, counts[k] is the number of its values,
+	// arrays[k] is the position of $k+1 passed as a whole inside ANY(...)
+	starts := make([]int, len(args))
+	counts := make([]int, len(args))
+	arrays := make([]int, len(args))
+	expanded := make([]interface{}, 0, len(args))
+
+	for k, a := range args {
+		starts[k] = len(expanded) + 1
+
+		in, ok := a.(InValues)
+		if !ok {
+			expanded = append(expanded, a)
+			counts[k] = 1
+			continue
+		}
+
+		v := reflect.ValueOf(in.Values)
+		if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+			return "", nil, fmt.Errorf("argument $%d: In expects a slice or an array but got %T", k+1, in.Values)
+		}
+		if asList[k] {
+			if v.Len() == 0 {
+				if emptyIn == EmptyInError {
+					return "", nil, fmt.Errorf("argument $%d: %w", k+1, ErrEmptyIn)
+				}
+				// an empty array for "= ANY($N)", a nil slice would be sent as NULL
+				expanded = append(expanded, reflect.MakeSlice(reflect.SliceOf(v.Type().Elem()), 0, 0).Interface())
+			}
+			for j := 0; j < v.Len(); j++ {
+				expanded = append(expanded, v.Index(j).Interface())
+			}
+			counts[k] = v.Len()
+		}
+		if asArray[k] {
+			arrays[k] = len(expanded) + 1
+			expanded = append(expanded, in.Values)
+		}
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(query) + 4*len(expanded))
+
+	prev := 0
+	for _, ph := range phs {
+		k := ph.k
+		_, isIn := args[k].(InValues)
+
+		switch {
+		case !isIn:
+			sb.WriteString(query[prev:ph.start])
+			writePlaceholder(&sb, starts[k])
+		case ph.array:
+			sb.WriteString(query[prev:ph.start])
+			writePlaceholder(&sb, arrays[k])
+		case counts[k] == 0:
+			if ph.listEnd == 0 {
+				return "", nil, fmt.Errorf("argument $%d: an empty In must be the only element of IN (...) with EmptyInFalse", k+1)
+			}
+			// "x IN ()" is false and "x NOT IN ()" is true even for a NULL x, unlike "x IN (NULL)"
+			sb.WriteString(query[prev:ph.listStart])
+			if ph.not {
+				sb.WriteString("<> ALL(")
+			} else {
+				sb.WriteString("= ANY(")
+			}
+			writePlaceholder(&sb, starts[k])
+			sb.WriteByte(')')
+			prev = ph.listEnd
+			continue
+		default:
+			sb.WriteString(query[prev:ph.start])
+			for j := 0; j < counts[k]; j++ {
+				if j > 0 {
+					sb.WriteString(", ")
+				}
+				writePlaceholder(&sb, starts[k]+j)
+			}
+		}
+		prev = ph.end
+	}
+	sb.WriteString(query[prev:])
+
+	return sb.String(), expanded, nil
+}
+
+// findPlaceholders returns the $N of query outside of literals and comments, N must be between 1 and argc.
+func findPlaceholders(query string, argc int) ([]placeholder, error) {
+	var phs []placeholder
+
+	n := len(query)
+	for i := 0; i < n; {
+		end, err := skipLiteral(query, i)
+		if err != nil {
+			return nil, err
+		}
+		if end > i {
+			i = end
+			continue
+		}
+
+		if query[i] != '$' || i+1 >= n || !isDigit(query[i+1]) || (i > 0 && isIdentChar(query[i-1])) {
+			i++
+			continue
+		}
+
+		end = i + 1
+		for end < n && isDigit(query[end]) {
+			end++
+		}
+		pos, err := strconv.Atoi(query[i+1 : end])
+		if err != nil || pos < 1 || pos > argc {
+			// pgx reports the wrong number of arguments
+			i = end
+			continue
+		}
+
+		ph := placeholder{start: i, end: end, k: pos - 1}
+		if open := skipSpaceBack(query, i); open > 0 && query[open-1] == '(' {
+			word, wordStart := wordBefore(query, open-1)
+			switch {
+			case strings.EqualFold(word, "ANY") || strings.EqualFold(word, "ALL") || strings.EqualFold(word, "SOME"):
+				ph.array = true
+			case strings.EqualFold(word, "IN"):
+				closing := end
+				for closing < n && isSpace(query[closing]) {
+					closing++
+				}
+				if closing < n && query[closing] == ')' {
+					ph.listStart, ph.listEnd = wordStart, closing+1
+					if not, notStart := wordBefore(query, wordStart); strings.EqualFold(not, "NOT") {
+						ph.listStart, ph.not = notStart, true
+					}
+				}
+			}
+		}
+		phs = append(phs, ph)
+		i = end
+	}
+
+	return phs, nil
+}
+
+func writePlaceholder(sb *strings.Builder, pos int) {
+	sb.WriteByte('$')
+	sb.WriteString(strconv.Itoa(pos))
+}
+
+// skipSpaceBack returns the position right after the last non-space character of query[:i].
+func skipSpaceBack(query string, i int) int {
+	for i > 0 && isSpace(query[i-1]) {
+		i--
+	}
+	return i
+}
+
+// wordBefore returns the identifier that ends right before query[i], skipping spaces, and its start.
+func wordBefore(query string, i int) (string, int) {
+	end := skipSpaceBack(query, i)
+	start := end
+	for start > 0 && isIdentChar(query[start-1]) {
+		start--
+	}
+	return query[start:end], start
+}
@@ -0,0 +1,122 @@
+package easyscan
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+func Test_expandIn(t *testing.T) {
+	t.Run("no In arguments", func(t *testing.T) {
+		args := []interface{}{1, "foo"}
+		query, got, err := expandIn("SELECT $1, $2", args, EmptyInError)
+		noError(t, err)
+		equal(t, "SELECT $1, $2", query)
+		equal(t, args, got)
+	})
+
+	t.Run("renumbering", func(t *testing.T) {
+		query, args, err := expandIn(
+			"SELECT * FROM t WHERE a = $1 AND id IN ($2) AND b = $3 AND c IN ($4) OR d = $3",
+			[]interface{}{"a", In([]int{1, 2, 3}), "b", In([2]string{"x", "y"})},
+			EmptyInError)
+		noError(t, err)
+		equal(t, "SELECT * FROM t WHERE a = $1 AND id IN ($2, $3, $4) AND b = $5 AND c IN ($6, $7) OR d = $5", query)
+		equal(t, []interface{}{"a", 1, 2, 3, "b", "x", "y"}, args)
+	})
+
+	t.Run("literals", func(t *testing.T) {
+		query, _, err := expandIn("SELECT '$1', $$ $2 $$, \"$1\" -- $1\n, $1, a$1", []interface{}{In([]int{1, 2}), 3}, EmptyInError)
+		noError(t, err)
+		equal(t, "SELECT '$1', $$ $2 $$, \"$1\" -- $1\n, $1, $2, a$1", query)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		_, _, err := expandIn("SELECT 1 WHERE id IN ($1)", []interface{}{In([]int{})}, EmptyInError)
+		equal(t, true, errors.Is(err, ErrEmptyIn))
+		errorContains(t, err, "argument $1")
+	})
+
+	t.Run("empty as false", func(t *testing.T) {
+		query, args, err := expandIn("SELECT 1 WHERE id IN ($1) AND a = $2", []interface{}{In([]int(nil)), "a"}, EmptyInFalse)
+		noError(t, err)
+		equal(t, "SELECT 1 WHERE id = ANY($1) AND a = $2", query)
+		equal(t, []interface{}{[]int{}, "a"}, args)
+	})
+
+	t.Run("empty NOT IN", func(t *testing.T) {
+		query, args, err := expandIn("SELECT 1 WHERE id NOT IN ( $1 ) AND a = $2", []interface{}{In([0]int{}), "a"}, EmptyInFalse)
+		noError(t, err)
+		equal(t, "SELECT 1 WHERE id <> ALL($1) AND a = $2", query)
+		equal(t, []interface{}{[]int{}, "a"}, args)
+	})
+
+	t.Run("empty among other elements", func(t *testing.T) {
+		_, _, err := expandIn("SELECT 1 WHERE id IN ($1, 5)", []interface{}{In([]int{})}, EmptyInFalse)
+		errorContains(t, err, "must be the only element")
+	})
+
+	t.Run("ANY", func(t *testing.T) {
+		ids := []int{1, 2}
+		query, args, err := expandIn(
+			"SELECT 1 WHERE id = ANY($1) AND a = $2 AND b <> all ( $1 ) AND c IN ($1)",
+			[]interface{}{In(ids), "a"}, EmptyInError)
+		noError(t, err)
+		equal(t, "SELECT 1 WHERE id = ANY($3) AND a = $4 AND b <> all ( $3 ) AND c IN ($1, $2)", query)
+		equal(t, []interface{}{1, 2, ids, "a"}, args)
+	})
+
+	t.Run("empty ANY", func(t *testing.T) {
+		query, args, err := expandIn("SELECT 1 WHERE id = ANY($1)", []interface{}{In([]int{})}, EmptyInError)
+		noError(t, err)
+		equal(t, "SELECT 1 WHERE id = ANY($1)", query)
+		equal(t, []interface{}{[]int{}}, args)
+	})
+
+	t.Run("not a slice", func(t *testing.T) {
+		_, _, err := expandIn("SELECT $1", []interface{}{In(1)}, EmptyInError)
+		errorContains(t, err, "In expects a slice or an array")
+	})
+}
+
+func TestSelectIn(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	pool, err := pgxpool.Connect(ctx, connString)
+	noError(t, err)
+	defer pool.Close()
+
+	const query = "SELECT * FROM generate_series(1, 10) AS id WHERE id IN ($1) AND id > $2"
+
+	var result []int
+	err = Select(ctx, pool, &result, query, In([]int{2, 4, 6}), 3)
+	noError(t, err)
+	equal(t, []int{4, 6}, result)
+
+	err = Select(ctx, pool, &result, query, In([]int{}), 3)
+	equal(t, true, errors.Is(err, ErrEmptyIn))
+
+	result = nil
+	err = Select(ctx, pool, &result, query, In([]int{}), 3, WithEmptyIn(EmptyInFalse))
+	noError(t, err)
+	equal(t, 0, len(result))
+
+	result = nil
+	err = Select(ctx, pool, &result, "SELECT * FROM generate_series(1, 3) AS id WHERE id NOT IN ($1)",
+		In([]int{}), WithEmptyIn(EmptyInFalse))
+	noError(t, err)
+	equal(t, []int{1, 2, 3}, result)
+
+	result = nil
+	err = Select(ctx, pool, &result, "SELECT * FROM generate_series(1, 10) AS id WHERE id = ANY($1) AND id > $2",
+		In([]int{2, 4, 6}), 3)
+	noError(t, err)
+	equal(t, []int{4, 6}, result)
+
+	var id int
+	err = Get(ctx, pool, &id, query, In([]int{7}), 3)
+	noError(t, err)
+	equal(t, 7, id)
+}
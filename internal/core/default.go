@@ -0,0 +1,95 @@
+package core
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TagDefault is the tag option with the value of a field for NULL and for a column missing from the result,
+// e.g. db:"currency,default=USD". The value can't contain commas.
+const TagDefault = "default"
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// parseDefaultOption parses the default option of f for the field type t, if there is one.
+func (f *Field) parseDefaultOption(t reflect.Type) {
+	prefix := TagDefault + "="
+	for _, o := range f.Options {
+		if strings.HasPrefix(o, prefix) {
+			f.def, f.defErr = parseDefault(t, o[len(prefix):])
+			return
+		}
+	}
+}
+
+// HasDefault reports whether the field has a valid default value.
+func (f Field) HasDefault() bool {
+	return f.def.IsValid()
+}
+
+// DefaultError returns the error of parsing the default value of the field, if any.
+func (f Field) DefaultError() error {
+	return f.defErr
+}
+
+// setDefault sets the default value to the field v, pointers are allocated for every call.
+func (f Field) setDefault(v reflect.Value) {
+	if f.def.Kind() == reflect.Ptr {
+		p := reflect.New(f.def.Type().Elem())
+		p.Elem().Set(f.def.Elem())
+		v.Set(p)
+		return
+	}
+	v.Set(f.def)
+}
+
+// parseDefault converts s into a value of t, pointers to the supported types point to the parsed value.
+func parseDefault(t reflect.Type, s string) (reflect.Value, error) {
+	v := reflect.New(t).Elem()
+	target := v
+	if t.Kind() == reflect.Ptr {
+		p := reflect.New(t.Elem())
+		v.Set(p)
+		target = p.Elem()
+	}
+
+	var err error
+	switch {
+	case target.Type() == durationType:
+		var d time.Duration
+		d, err = time.ParseDuration(s)
+		target.SetInt(int64(d))
+	case target.Type() == timeType:
+		var tm time.Time
+		tm, err = time.Parse(time.RFC3339, s)
+		target.Set(reflect.ValueOf(tm))
+	case target.Kind() == reflect.String:
+		target.SetString(s)
+	case target.Kind() == reflect.Bool:
+		var b bool
+		b, err = strconv.ParseBool(s)
+		target.SetBool(b)
+	case target.Kind() >= reflect.Int && target.Kind() <= reflect.Int64:
+		var i int64
+		i, err = strconv.ParseInt(s, 10, target.Type().Bits())
+		target.SetInt(i)
+	case target.Kind() >= reflect.Uint && target.Kind() <= reflect.Uint64:
+		var u uint64
+		u, err = strconv.ParseUint(s, 10, target.Type().Bits())
+		target.SetUint(u)
+	case target.Kind() == reflect.Float32 || target.Kind() == reflect.Float64:
+		var fl float64
+		fl, err = strconv.ParseFloat(s, target.Type().Bits())
+		target.SetFloat(fl)
+	default:
+		return reflect.Value{}, fmt.Errorf("default values aren't supported for %s", t)
+	}
+
+	if err != nil {
+		return reflect.Value{}, fmt.Errorf("invalid default value %q for %s: %w", s, t, err)
+	}
+	return v, nil
+}
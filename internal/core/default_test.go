@@ -0,0 +1,87 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseDefault(t *testing.T) {
+	cases := []struct {
+		value    interface{}
+		def      string
+		expected interface{}
+	}{
+		{"", "USD", "USD"},
+		{0, "3", 3},
+		{int8(0), "-7", int8(-7)},
+		{uint16(0), "8", uint16(8)},
+		{float32(0), "1.5", float32(1.5)},
+		{false, "true", true},
+		{time.Duration(0), "1m30s", 90 * time.Second},
+		{time.Time{}, "2012-03-04T10:11:12Z", time.Date(2012, 3, 4, 10, 11, 12, 0, time.UTC)},
+	}
+	for _, c := range cases {
+		v, err := parseDefault(reflect.TypeOf(c.value), c.def)
+		noError(t, err)
+		equal(t, c.expected, v.Interface())
+	}
+
+	v, err := parseDefault(reflect.TypeOf(new(int)), "5")
+	noError(t, err)
+	equal(t, 5, *v.Interface().(*int))
+
+	_, err = parseDefault(reflect.TypeOf(int8(0)), "300")
+	errorContains(t, err, `invalid default value "300" for int8`)
+
+	_, err = parseDefault(reflect.TypeOf([]int{}), "1")
+	errorContains(t, err, "default values aren't supported for []int")
+}
+
+func TestDefault(t *testing.T) {
+	type payment struct {
+		ID       int     `db:"id"`
+		Currency string  `db:"currency,default=USD"`
+		Retries  *int    `db:"retries,default=3"`
+		Note     *string `db:"note"`
+	}
+
+	t.Run("null and missing columns", func(t *testing.T) {
+		var result []*payment
+		s, err := NewSlice(&result)
+		noError(t, err)
+
+		err = s.Scan(newSliceRows([]string{"id", "currency"},
+			[]interface{}{1, "EUR"},
+			[]interface{}{2, nil},
+		), Options{})
+		noError(t, err)
+
+		equal(t, "EUR", result[0].Currency)
+		equal(t, "USD", result[1].Currency)
+		equal(t, 3, *result[0].Retries)
+		equal(t, 3, *result[1].Retries)
+		// every row gets its own pointer
+		equal(t, false, result[0].Retries == result[1].Retries)
+	})
+
+	t.Run("row", func(t *testing.T) {
+		var result payment
+		row, err := NewRow(&result)
+		noError(t, err)
+
+		err = row.Scan(newSliceRows([]string{"id", "retries"}, []interface{}{1, 5}), Options{})
+		noError(t, err)
+		equal(t, "USD", result.Currency)
+		equal(t, 5, *result.Retries)
+	})
+
+	t.Run("invalid default", func(t *testing.T) {
+		type broken struct {
+			Retries int `db:"retries,default=many"`
+		}
+		problems := ValidateType(reflect.TypeOf(broken{}), map[string]bool{TagDefault: true})
+		equal(t, 1, len(problems))
+		equal(t, `invalid default value "many" for int: strconv.ParseInt: parsing "many": invalid syntax`, problems[0].Message)
+	})
+}
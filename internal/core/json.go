@@ -0,0 +1,112 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// TagJSON is the tag option that decodes a json or jsonb column into a field of any type.
+const TagJSON = "json"
+
+// JSONCodec decodes json columns.
+type JSONCodec interface {
+	Unmarshal(data []byte, v interface{}) error
+	NewDecoder(r io.Reader) JSONDecoder
+}
+
+// JSONDecoder reads JSON values from a stream, *json.Decoder implements it.
+type JSONDecoder interface {
+	Decode(v interface{}) error
+}
+
+// JSONStreamDecoder is implemented by field types that decode large documents themselves,
+// e.g. element by element, instead of unmarshaling the whole document at once.
+// The decoder reads the column bytes the driver received, so the document isn't
+// copied or unmarshaled into an intermediate value.
+type JSONStreamDecoder interface {
+	DecodeJSON(dec JSONDecoder) error
+}
+
+type stdJSON struct{}
+
+func (stdJSON) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+func (stdJSON) NewDecoder(r io.Reader) JSONDecoder {
+	return json.NewDecoder(r)
+}
+
+// StdJSON is the JSONCodec of encoding/json, it is used when no other codec is set.
+var StdJSON JSONCodec = stdJSON{}
+
+// jsonTarget is the scan destination of a field tagged with json.
+type jsonTarget struct {
+	field  reflect.Value
+	codec  JSONCodec
+	column string
+}
+
+func newJSONTarget(field reflect.Value, column string, codec JSONCodec) *jsonTarget {
+	if codec == nil {
+		codec = StdJSON
+	}
+	return &jsonTarget{field: field, codec: codec, column: column}
+}
+
+// Scan decodes the json document into a zeroed field, so that maps and slices of previous rows aren't reused.
+func (j *jsonTarget) Scan(src interface{}) error {
+	j.field.Set(reflect.Zero(j.field.Type()))
+	ptr := j.field.Addr().Interface()
+	sd, stream := ptr.(JSONStreamDecoder)
+
+	switch s := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		if stream {
+			return sd.DecodeJSON(j.codec.NewDecoder(bytes.NewReader(s)))
+		}
+		return j.codec.Unmarshal(s, ptr)
+	case string:
+		if stream {
+			return sd.DecodeJSON(j.codec.NewDecoder(strings.NewReader(s)))
+		}
+		return j.codec.Unmarshal([]byte(s), ptr)
+	}
+	return fmt.Errorf("column %q isn't json, got %T", j.column, src)
+}
+
+// IsJSONType reports whether a column of the type ct holds JSON.
+// Types reported without an OID are recognized by name.
+func IsJSONType(ct ColumnType) bool {
+	switch ct.OID {
+	case oidJSON, oidJSONB:
+		return true
+	case 0:
+		return ct.Name == "" || ct.Name == "json" || ct.Name == "jsonb"
+	}
+	return false
+}
+
+// checkJSONColumn rejects a column of a json tagged field that doesn't hold JSON.
+func checkJSONColumn(rows Rows, idx int, column string, t reflect.Type, f Field) error {
+	ct, ok := rows.(ColumnTyper)
+	if !ok {
+		return nil
+	}
+	types := ct.ColumnTypes()
+	if idx >= len(types) || IsJSONType(types[idx]) {
+		return nil
+	}
+
+	name := types[idx].Name
+	if name == "" {
+		name = fmt.Sprintf("oid %d", types[idx].OID)
+	}
+	return fmt.Errorf("column %q of type %s isn't json and can't be decoded into %s", column, name, f.Path(t))
+}
@@ -0,0 +1,117 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+)
+
+type jsonPayload struct {
+	Kind  string `json:"kind"`
+	Count int    `json:"count"`
+}
+
+// jsonItems counts the elements of a json array while reading it from the stream.
+type jsonItems struct {
+	n int
+}
+
+func (j *jsonItems) DecodeJSON(dec JSONDecoder) error {
+	d := dec.(*json.Decoder)
+	if _, err := d.Token(); err != nil {
+		return err
+	}
+	for d.More() {
+		var item json.RawMessage
+		if err := d.Decode(&item); err != nil {
+			return err
+		}
+		j.n++
+	}
+	_, err := d.Token()
+	return err
+}
+
+// upperCodec is a JSONCodec that records its use.
+type upperCodec struct {
+	calls *int
+}
+
+func (c upperCodec) Unmarshal(data []byte, v interface{}) error {
+	*c.calls++
+	return json.Unmarshal(data, v)
+}
+
+func (c upperCodec) NewDecoder(r io.Reader) JSONDecoder {
+	return json.NewDecoder(r)
+}
+
+type jsonRow struct {
+	ID      int               `db:"id"`
+	Payload jsonPayload       `db:"payload,json"`
+	Tags    map[string]string `db:"tags,json"`
+	Items   jsonItems         `db:"items,json"`
+}
+
+func TestJSON(t *testing.T) {
+	rows := func() *sliceRows {
+		return newSliceRows([]string{"id", "payload", "tags", "items"},
+			[]interface{}{1, []byte(`{"kind":"a","count":2}`), `{"x":"1"}`, []byte(`[1,2,3]`)},
+			[]interface{}{2, nil, `{"y":"2"}`, `[4]`},
+		)
+	}
+
+	t.Run("decode", func(t *testing.T) {
+		var result []jsonRow
+		s, err := NewSlice(&result)
+		noError(t, err)
+
+		err = s.Scan(rows(), Options{})
+		noError(t, err)
+		equal(t, jsonPayload{Kind: "a", Count: 2}, result[0].Payload)
+		equal(t, jsonPayload{}, result[1].Payload)
+		// maps of different rows aren't shared
+		equal(t, map[string]string{"x": "1"}, result[0].Tags)
+		equal(t, map[string]string{"y": "2"}, result[1].Tags)
+		equal(t, 3, result[0].Items.n)
+		equal(t, 1, result[1].Items.n)
+	})
+
+	t.Run("codec", func(t *testing.T) {
+		calls := 0
+		var result []jsonRow
+		s, err := NewSlice(&result)
+		noError(t, err)
+
+		err = s.Scan(rows(), Options{JSONCodec: upperCodec{calls: &calls}})
+		noError(t, err)
+		equal(t, 3, calls)
+	})
+
+	t.Run("not json column", func(t *testing.T) {
+		var result jsonRow
+		row, err := NewRow(&result)
+		noError(t, err)
+
+		err = row.Scan(typedRows{
+			sliceRows: newSliceRows([]string{"id", "payload"}, []interface{}{1, "x"}),
+			types:     []ColumnType{{OID: oidInt4, Name: "int4"}, {OID: oidText, Name: "text"}},
+		}, Options{})
+		errorContains(t, err, `column "payload" of type text isn't json and can't be decoded into jsonRow.Payload`)
+	})
+
+	t.Run("not json value", func(t *testing.T) {
+		var result jsonRow
+		row, err := NewRow(&result)
+		noError(t, err)
+
+		err = row.Scan(newSliceRows([]string{"payload"}, []interface{}{42}), Options{})
+		var se *ScanError
+		if !errors.As(err, &se) {
+			t.Fatalf("expected *ScanError but got %v", err)
+		}
+		equal(t, "payload", se.Column)
+		errorContains(t, err, `column "payload" isn't json, got int`)
+	})
+}
@@ -0,0 +1,62 @@
+package core
+
+import "reflect"
+
+// TagNullZero is the tag option that makes a NULL scan as the zero value of the field.
+const TagNullZero = "nullzero"
+
+// nullTarget scans a column through a pointer, so that the driver accepts NULL,
+// and copies the result into the field afterwards. NULL is replaced by the zero or the default value.
+type nullTarget struct {
+	// holder is a *T the driver allocates for non NULL values,
+	// the field itself when it is a pointer and invalid for a field missing from the result
+	holder reflect.Value
+	field  reflect.Value
+	// direct reports whether the holder is the field
+	direct bool
+	// def is the field with the default value, nil for the zero value
+	def *Field
+}
+
+func newNullTarget(field reflect.Value, def *Field) nullTarget {
+	if field.Kind() == reflect.Ptr {
+		return nullTarget{holder: field, field: field, def: def, direct: true}
+	}
+	return nullTarget{holder: reflect.New(reflect.PtrTo(field.Type())).Elem(), field: field, def: def}
+}
+
+// scan returns the destination passed to the driver.
+func (n nullTarget) scan() interface{} {
+	return n.holder.Addr().Interface()
+}
+
+func (n nullTarget) apply() {
+	if !n.holder.IsValid() || n.holder.IsNil() {
+		if n.def != nil {
+			n.def.setDefault(n.field)
+		} else {
+			n.field.Set(reflect.Zero(n.field.Type()))
+		}
+		return
+	}
+	if n.direct {
+		return
+	}
+	n.field.Set(n.holder.Elem())
+	n.holder.Set(reflect.Zero(n.holder.Type()))
+}
+
+func applyNullTargets(targets []nullTarget) {
+	for _, n := range targets {
+		n.apply()
+	}
+}
+
+// AcceptsNull reports whether the driver scans NULL into t without an error.
+func AcceptsNull(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Map, reflect.Interface:
+		return true
+	}
+	return implementsScanner(t)
+}
@@ -0,0 +1,77 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNullZero(t *testing.T) {
+	type user struct {
+		ID       int     `db:"id"`
+		Nickname string  `db:"nickname,nullzero"`
+		Email    *string `db:"email,nullzero"`
+		Age      int     `db:"age"`
+	}
+
+	rows := func() *sliceRows {
+		return newSliceRows([]string{"id", "nickname", "email", "age"},
+			[]interface{}{1, "foo", "foo@example.com", 30},
+			[]interface{}{2, nil, nil, 40},
+		)
+	}
+
+	t.Run("tag", func(t *testing.T) {
+		var result []user
+		s, err := NewSlice(&result)
+		noError(t, err)
+
+		err = s.Scan(rows(), Options{})
+		noError(t, err)
+		equal(t, 2, len(result))
+		equal(t, "foo", result[0].Nickname)
+		equal(t, "foo@example.com", *result[0].Email)
+		equal(t, "", result[1].Nickname)
+		equal(t, (*string)(nil), result[1].Email)
+		equal(t, 40, result[1].Age)
+	})
+
+	t.Run("option", func(t *testing.T) {
+		var result []*user
+		s, err := NewSlice(&result)
+		noError(t, err)
+
+		err = s.Scan(newSliceRows([]string{"id", "age"}, []interface{}{1, 30}, []interface{}{nil, nil}), Options{NullZero: true})
+		noError(t, err)
+		equal(t, &user{ID: 1, Age: 30}, result[0])
+		equal(t, &user{}, result[1])
+	})
+
+	t.Run("supported type", func(t *testing.T) {
+		var result int
+		row, err := NewRow(&result)
+		noError(t, err)
+
+		result = 5
+		err = row.Scan(newSliceRows([]string{"sum"}, []interface{}{nil}), Options{NullZero: true})
+		noError(t, err)
+		equal(t, 0, result)
+
+		var results []int
+		s, err := NewSlice(&results)
+		noError(t, err)
+		err = s.Scan(newSliceRows([]string{"n"}, []interface{}{1}, []interface{}{nil}), Options{NullZero: true})
+		noError(t, err)
+		equal(t, []int{1, 0}, results)
+	})
+
+	t.Run("direct scan without nullzero", func(t *testing.T) {
+		type plain struct {
+			ID int `db:"id"`
+		}
+		var p plain
+		scans, targets, err := getSliceForScan(reflect.TypeOf(p), newSliceRows([]string{"id"}), reflect.ValueOf(&p), Options{})
+		noError(t, err)
+		equal(t, 0, len(targets))
+		equal(t, interface{}(&p.ID), scans[0])
+	})
+}
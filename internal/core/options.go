@@ -0,0 +1,162 @@
+package core
+
+import (
+	"fmt"
+	"time"
+)
+
+// Option changes the behavior of a single Get or Select call.
+// Options are passed among the query arguments and are removed before the query is sent.
+type Option interface {
+	Apply(*Options)
+}
+
+// OptionFunc is an adapter to use an ordinary function as an Option.
+type OptionFunc func(*Options)
+
+// Apply calls f(o).
+func (f OptionFunc) Apply(o *Options) {
+	f(o)
+}
+
+// Mode defines what Select does with rows already present in the destination slice.
+type Mode int
+
+const (
+	// ModeAppend appends scanned rows after the existing elements. This is the default.
+	ModeAppend Mode = iota
+	// ModeReplace discards the existing elements before the first row is added.
+	ModeReplace
+)
+
+// EmptyIn defines how an In argument without elements is expanded.
+type EmptyIn int
+
+const (
+	// EmptyInError makes the call fail. This is the default.
+	EmptyInError EmptyIn = iota
+	// EmptyInFalse turns "IN ($N)" into a false and "NOT IN ($N)" into a true predicate.
+	EmptyInFalse
+)
+
+// InValues is a slice argument the pgx v4 package expands into one placeholder per element.
+type InValues struct {
+	// Values is a slice or an array.
+	Values interface{}
+}
+
+// Order is a column of ORDER BY.
+type Order struct {
+	Column string
+	Desc   bool
+}
+
+// RetryPolicy defines how a failed call is repeated.
+type RetryPolicy struct {
+	// MaxAttempts is the number of runs including the first one.
+	MaxAttempts int
+	// MinBackoff is the delay before the second run, it doubles for every next run.
+	MinBackoff time.Duration
+	// MaxBackoff limits the delay.
+	MaxBackoff time.Duration
+}
+
+// Options holds the settings of a single call.
+type Options struct {
+	Mode         Mode
+	ExpectedRows int
+	EmptyIn      EmptyIn
+	OrderBy      []Order
+	Limit        int
+	Offset       int
+	Retry        *RetryPolicy
+	// CheckTypes enables the comparison of column types with destination types before the first row is scanned.
+	CheckTypes bool
+	// NullZero makes NULL scan as the zero value into fields that can't hold NULL.
+	NullZero bool
+	// JSONCodec decodes fields tagged with json, StdJSON when nil.
+	JSONCodec JSONCodec
+}
+
+// WithMode sets the Mode of Select.
+func WithMode(m Mode) Option {
+	return OptionFunc(func(o *Options) {
+		o.Mode = m
+	})
+}
+
+// WithExpectedRows makes Select reserve capacity for n rows before scanning.
+func WithExpectedRows(n int) Option {
+	return OptionFunc(func(o *Options) {
+		o.ExpectedRows = n
+	})
+}
+
+// WithTypeCheck makes Get and Select compare the type of every column with the type of its destination
+// before the first row is scanned. All incompatible pairs are returned at once by a *TypeMismatchError.
+func WithTypeCheck() Option {
+	return OptionFunc(func(o *Options) {
+		o.CheckTypes = true
+	})
+}
+
+// WithNullZero makes NULL scan as the zero value into every field that can't hold NULL,
+// as if the fields were tagged with the nullzero option.
+func WithNullZero() Option {
+	return OptionFunc(func(o *Options) {
+		o.NullZero = true
+	})
+}
+
+// WithJSONCodec sets the codec that decodes fields tagged with json, encoding/json is used by default.
+func WithJSONCodec(c JSONCodec) Option {
+	return OptionFunc(func(o *Options) {
+		o.JSONCodec = c
+	})
+}
+
+// CheckRootOnly returns an error for the options and arguments that only the pgx v4 package implements,
+// so that the other packages don't ignore them silently. pkg is the name of the calling package.
+func CheckRootOnly(pkg string, opts Options, args []interface{}) error {
+	if opts.Retry != nil {
+		return fmt.Errorf("%s: WithRetry isn't supported", pkg)
+	}
+	if opts.EmptyIn != EmptyInError {
+		return fmt.Errorf("%s: WithEmptyIn isn't supported", pkg)
+	}
+	for i, a := range args {
+		if _, ok := a.(InValues); ok {
+			return fmt.Errorf("%s: argument $%d: In isn't supported", pkg, i+1)
+		}
+	}
+	return nil
+}
+
+// ExtractOptions splits args into options and query arguments.
+// args is returned as is when it contains no options.
+func ExtractOptions(args []interface{}) (Options, []interface{}) {
+	var opts Options
+
+	idx := -1
+	for i, a := range args {
+		if _, ok := a.(Option); ok {
+			idx = i
+			break
+		}
+	}
+	if idx == -1 {
+		return opts, args
+	}
+
+	queryArgs := make([]interface{}, idx, len(args))
+	copy(queryArgs, args[:idx])
+	for _, a := range args[idx:] {
+		if o, ok := a.(Option); ok {
+			o.Apply(&opts)
+			continue
+		}
+		queryArgs = append(queryArgs, a)
+	}
+
+	return opts, queryArgs
+}
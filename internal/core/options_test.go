@@ -0,0 +1,43 @@
+package core
+
+import (
+	"testing"
+)
+
+func Test_ExtractOptions(t *testing.T) {
+	withMode := OptionFunc(func(o *Options) { o.Mode = ModeReplace })
+	withExpectedRows := OptionFunc(func(o *Options) { o.ExpectedRows = 10 })
+
+	t.Run("no options", func(t *testing.T) {
+		args := []interface{}{1, "foo"}
+		opts, queryArgs := ExtractOptions(args)
+		equal(t, Options{}, opts)
+		equal(t, args, queryArgs)
+	})
+
+	t.Run("mixed", func(t *testing.T) {
+		args := []interface{}{1, withMode, "foo", withExpectedRows}
+		opts, queryArgs := ExtractOptions(args)
+		equal(t, Options{Mode: ModeReplace, ExpectedRows: 10}, opts)
+		equal(t, []interface{}{1, "foo"}, queryArgs)
+	})
+
+	t.Run("nil args", func(t *testing.T) {
+		opts, queryArgs := ExtractOptions(nil)
+		equal(t, Options{}, opts)
+		equal(t, 0, len(queryArgs))
+	})
+}
+
+func TestCheckRootOnly(t *testing.T) {
+	noError(t, CheckRootOnly("pgxv5", Options{Mode: ModeReplace, NullZero: true}, []interface{}{1, "a"}))
+
+	err := CheckRootOnly("pgxv5", Options{Retry: &RetryPolicy{}}, nil)
+	errorContains(t, err, "pgxv5: WithRetry isn't supported")
+
+	err = CheckRootOnly("sqlscan", Options{EmptyIn: EmptyInFalse}, nil)
+	errorContains(t, err, "sqlscan: WithEmptyIn isn't supported")
+
+	err = CheckRootOnly("pgxv5", Options{}, []interface{}{1, InValues{Values: []int{1}}})
+	errorContains(t, err, "pgxv5: argument $2: In isn't supported")
+}
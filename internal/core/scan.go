@@ -0,0 +1,338 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+var (
+	// ErrMoreThanOneRow is returned by Row.Scan when the result has more than one row.
+	ErrMoreThanOneRow = errors.New("get expects 1 row")
+	// ErrNoRows is returned by Row.Scan when the result is empty.
+	// Drivers replace it with their own sentinel.
+	ErrNoRows = errors.New("no rows in result set")
+)
+
+var emptyScanObj = emptyScan{}
+
+type emptyScan struct {
+}
+
+func (emptyScan) Scan(_ interface{}) error {
+	return nil
+}
+
+// Rows is the part of a driver result set used for scanning.
+type Rows interface {
+	Next() bool
+	Scan(dest ...interface{}) error
+	Err() error
+	Columns() []string
+}
+
+// Row is a validated destination for a single row.
+type Row struct {
+	ptr         reflect.Value
+	typ         reflect.Type
+	isSupported bool
+}
+
+// NewRow checks that dest is a non nil pointer to a struct or to a driver supported type.
+func NewRow(dest interface{}) (Row, error) {
+	objectPtr := reflect.ValueOf(dest)
+	if objectPtr.Kind() != reflect.Ptr || objectPtr.IsNil() {
+		return Row{}, errors.New("destination must be a non nil pointer")
+	}
+
+	objectType := objectPtr.Type().Elem()
+
+	isSupported := isPgxSupportedType(objectType, true)
+	if objectType.Kind() != reflect.Struct && !isSupported {
+		return Row{}, fmt.Errorf("expected a struct or a pgx supported type but got %s", objectType.Kind())
+	}
+
+	return Row{ptr: objectPtr, typ: objectType, isSupported: isSupported}, nil
+}
+
+// Scan reads exactly one row into the destination.
+func (r Row) Scan(rows Rows, opts Options) error {
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		return ErrNoRows
+	}
+
+	if opts.CheckTypes {
+		if err := checkTypes(rows, r.typ, !r.isSupported); err != nil {
+			return err
+		}
+	}
+
+	var err error
+	var targets []nullTarget
+	if r.isSupported {
+		var scan interface{}
+		scan, targets = getSupportedScan(r.ptr, opts.NullZero)
+		err = scanRow(rows, []interface{}{scan}, 0, r.typ, false)
+	} else {
+		var scans []interface{}
+		scans, targets, err = getSliceForScan(r.typ, rows, r.ptr, opts)
+		if err != nil {
+			return err
+		}
+		err = scanRow(rows, scans, 0, r.typ, true)
+	}
+
+	if err != nil {
+		return err
+	}
+	applyNullTargets(targets)
+
+	if rows.Next() {
+		return ErrMoreThanOneRow
+	}
+
+	return rows.Err()
+}
+
+// Slice is a validated destination for many rows.
+type Slice struct {
+	slice        reflect.Value
+	exemplarType reflect.Type
+	isPtr        bool
+	isSupported  bool
+}
+
+// NewSlice checks that dest is a non nil pointer to a slice of structs or driver supported types,
+// or pointers to them.
+func NewSlice(dest interface{}) (Slice, error) {
+	slicePtr := reflect.ValueOf(dest)
+
+	if slicePtr.Kind() != reflect.Ptr || slicePtr.IsNil() {
+		return Slice{}, errors.New("destination must be a non nil pointer to slice")
+	}
+
+	slice := slicePtr.Elem()
+
+	sliceType := slice.Type()
+	if sliceType.Kind() != reflect.Slice {
+		return Slice{}, fmt.Errorf("expected a slice but got %s", slice.Type().Kind())
+	}
+
+	//example: is string, for dest = *[]string
+	sliceElemType := sliceType.Elem()
+
+	//[]*object or []object
+	isPtr := sliceElemType.Kind() == reflect.Ptr
+
+	exemplarType := sliceElemType
+	if isPtr {
+		exemplarType = exemplarType.Elem()
+	}
+
+	isSupported := isPgxSupportedType(exemplarType, true)
+	if exemplarType.Kind() != reflect.Struct && !isSupported {
+		return Slice{}, fmt.Errorf("expected a struct or a pointer to a struct in the slice but got %s", exemplarType.Kind())
+	}
+
+	return Slice{slice: slice, exemplarType: exemplarType, isPtr: isPtr, isSupported: isSupported}, nil
+}
+
+// Scan reads all rows into the destination slice.
+func (s Slice) Scan(rows Rows, opts Options) error {
+	prepareSlice(s.slice, opts)
+
+	if s.isSupported {
+		return scanToSupported(rows, s.isPtr, s.slice, s.exemplarType, opts)
+	}
+
+	return scanObjects(rows, s.isPtr, s.slice, s.exemplarType, opts)
+}
+
+func scanToSupported(rows Rows, isPtr bool, slice reflect.Value, exemplarType reflect.Type, opts Options) error {
+	for row := 0; rows.Next(); row++ {
+		if row == 0 && opts.CheckTypes {
+			if err := checkTypes(rows, exemplarType, false); err != nil {
+				return err
+			}
+		}
+
+		exemplarPointer := reflect.New(exemplarType)
+
+		scan, targets := getSupportedScan(exemplarPointer, opts.NullZero)
+		err := scanRow(rows, []interface{}{scan}, row, exemplarType, false)
+		if err != nil {
+			return err
+		}
+		applyNullTargets(targets)
+
+		if isPtr {
+			addToSlice(slice, exemplarPointer)
+		} else {
+			addToSlice(slice, exemplarPointer.Elem())
+		}
+	}
+	return rows.Err()
+}
+
+func scanObjects(rows Rows, isPtr bool, slice reflect.Value, exemplarType reflect.Type, opts Options) error {
+	if !rows.Next() {
+		return rows.Err()
+	}
+
+	if opts.CheckTypes {
+		if err := checkTypes(rows, exemplarType, true); err != nil {
+			return err
+		}
+	}
+
+	objectForFilling := reflect.New(exemplarType)
+	scans, targets, err := getSliceForScan(exemplarType, rows, objectForFilling, opts)
+	if err != nil {
+		return err
+	}
+
+	err = scanRow(rows, scans, 0, exemplarType, true)
+	if err != nil {
+		return err
+	}
+	applyNullTargets(targets)
+
+	for row := 1; rows.Next(); row++ {
+		if isPtr {
+			exemplarPtr := reflect.New(exemplarType)
+			exemplarPtr.Elem().Set(objectForFilling.Elem())
+			addToSlice(slice, exemplarPtr)
+		} else {
+			addToSlice(slice, objectForFilling.Elem())
+		}
+
+		err = scanRow(rows, scans, row, exemplarType, true)
+		if err != nil {
+			return err
+		}
+		applyNullTargets(targets)
+	}
+
+	if isPtr {
+		addToSlice(slice, objectForFilling)
+	} else {
+		addToSlice(slice, objectForFilling.Elem())
+	}
+
+	return rows.Err()
+}
+
+// prepareSlice applies the mode and the capacity hint to the destination slice.
+func prepareSlice(slice reflect.Value, opts Options) {
+	if opts.Mode == ModeReplace {
+		slice.Set(slice.Slice(0, 0))
+	}
+
+	l := slice.Len()
+	if opts.ExpectedRows <= 0 || slice.Cap()-l >= opts.ExpectedRows {
+		return
+	}
+
+	grown := reflect.MakeSlice(slice.Type(), l, l+opts.ExpectedRows)
+	reflect.Copy(grown, slice)
+	slice.Set(grown)
+}
+
+// addToSlice relies on reflect.Append, which reuses the spare capacity
+// and grows the backing array geometrically when it runs out.
+func addToSlice(slice reflect.Value, element reflect.Value) {
+	slice.Set(reflect.Append(slice, element))
+}
+
+// getSupportedScan returns the scan destination of a driver supported type ptr points to.
+func getSupportedScan(ptr reflect.Value, nullZero bool) (interface{}, []nullTarget) {
+	if nullZero && !AcceptsNull(ptr.Type().Elem()) {
+		n := newNullTarget(ptr.Elem(), nil)
+		return n.scan(), []nullTarget{n}
+	}
+	return ptr.Interface(), nil
+}
+
+// getSliceForScan returns the scan destinations of the columns of rows in the struct exemplarPointer points to.
+// Fields that must turn NULL into the zero or the default value are scanned through the returned targets,
+// which are applied after every row. Fields tagged with json are decoded by their own scanners.
+// Other fields are scanned directly.
+// Fields with a default value missing from columns get a target without a holder.
+func getSliceForScan(t reflect.Type, rows Rows, exemplarPointer reflect.Value, opts Options) ([]interface{}, []nullTarget, error) {
+	columns := rows.Columns()
+	scans := make([]interface{}, len(columns))
+
+	info := getTypeInfo(t)
+	tags := info.container
+
+	e := exemplarPointer.Elem()
+
+	matchingFailed := true
+	var targets []nullTarget
+
+	if !opts.NullZero && !info.hasTargets {
+		for idx, column := range columns {
+			s := tags.find(column, e)
+			if s != emptyScanObj {
+				matchingFailed = false
+			}
+			scans[idx] = s
+		}
+	} else {
+		for idx, column := range columns {
+			f, ok := tags.lookup(column)
+			if !ok {
+				scans[idx] = emptyScanObj
+				continue
+			}
+			matchingFailed = false
+
+			fv := f.Value(e)
+			if f.HasOption(TagJSON) {
+				if err := checkJSONColumn(rows, idx, column, t, f); err != nil {
+					return nil, nil, err
+				}
+				scans[idx] = newJSONTarget(fv, column, opts.JSONCodec)
+				continue
+			}
+			if f.HasDefault() {
+				n := newNullTarget(fv, &f)
+				targets = append(targets, n)
+				scans[idx] = n.scan()
+				continue
+			}
+			if (opts.NullZero || f.HasOption(TagNullZero)) && !AcceptsNull(fv.Type()) {
+				n := newNullTarget(fv, nil)
+				targets = append(targets, n)
+				scans[idx] = n.scan()
+				continue
+			}
+			scans[idx] = fv.Addr().Interface()
+		}
+
+		for i := range info.fields {
+			f := &info.fields[i]
+			if f.HasDefault() && !containsColumn(columns, f.Name) {
+				targets = append(targets, nullTarget{field: f.Value(e), def: f})
+			}
+		}
+	}
+
+	if matchingFailed {
+		return nil, nil, errors.New("db tags have no matches to columns")
+	}
+
+	return scans, targets, nil
+}
+
+func containsColumn(columns []string, column string) bool {
+	for _, c := range columns {
+		if c == column {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,144 @@
+package core
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ColumnType is the database type of a result column.
+type ColumnType struct {
+	// OID is the type OID, 0 when the driver doesn't report it.
+	OID uint32
+	// Name is the type name, empty when it isn't known.
+	Name string
+}
+
+// ColumnTyper is implemented by Rows that know the database types of the columns.
+type ColumnTyper interface {
+	ColumnTypes() []ColumnType
+}
+
+// ScanColumnIndexer is implemented by Rows whose driver reports the failed column in the Scan error,
+// pgx does it with ScanArgError. The row can't be scanned again to find the column, pgx closes the rows on the first failure.
+type ScanColumnIndexer interface {
+	ScanColumnIndex(err error) (int, bool)
+}
+
+// ScanError is a failure to scan a column into its destination.
+type ScanError struct {
+	// Row is the position of the row in the result, starting from 0.
+	Row int
+	// Column is the name of the failed column, empty when it can't be detected.
+	Column string
+	// Field is the path of the destination struct field, e.g. User.Audit.CreatedAt,
+	// empty when the destination isn't a struct.
+	Field string
+	// Type is the Go type of the destination.
+	Type reflect.Type
+	// ColumnType is the database type of the column.
+	ColumnType ColumnType
+	Err        error
+}
+
+func (e *ScanError) Error() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "rows.Scan: row %d", e.Row)
+	if e.Column != "" {
+		fmt.Fprintf(&sb, ", column %q", e.Column)
+	}
+	if e.ColumnType.Name != "" {
+		fmt.Fprintf(&sb, " (%s, oid %d)", e.ColumnType.Name, e.ColumnType.OID)
+	} else if e.ColumnType.OID != 0 {
+		fmt.Fprintf(&sb, " (oid %d)", e.ColumnType.OID)
+	}
+	if e.Field != "" {
+		fmt.Fprintf(&sb, " into %s (%s)", e.Field, e.Type)
+	} else if e.Type != nil {
+		fmt.Fprintf(&sb, " into %s", e.Type)
+	}
+	fmt.Fprintf(&sb, ": %v", e.Err)
+	return sb.String()
+}
+
+func (e *ScanError) Unwrap() error {
+	return e.Err
+}
+
+// scanRow scans the current row and describes a failure with a *ScanError.
+// t is the type of the destination, mapped reports whether scans were built from the tagged fields of t.
+func scanRow(rows Rows, scans []interface{}, row int, t reflect.Type, mapped bool) error {
+	err := rows.Scan(scans...)
+	if err == nil {
+		return nil
+	}
+
+	e := &ScanError{Row: row, Type: t, Err: err}
+	columns := rows.Columns()
+
+	col := -1
+	if indexer, ok := rows.(ScanColumnIndexer); ok {
+		if i, ok := indexer.ScanColumnIndex(err); ok && i < len(columns) {
+			col = i
+		}
+	} else if mapped && len(scans) == len(columns) {
+		col = failedColumn(rows, scans)
+	}
+	if col == -1 && len(columns) == 1 {
+		col = 0
+	}
+	if col == -1 {
+		return e
+	}
+
+	e.Column = columns[col]
+	if ct, ok := rows.(ColumnTyper); ok {
+		if types := ct.ColumnTypes(); col < len(types) {
+			e.ColumnType = types[col]
+		}
+	}
+
+	if mapped {
+		if f, ok := getTaggedFields(t).lookup(e.Column); ok {
+			e.Field, e.Type = f.idx.describe(t)
+		}
+	}
+	return e
+}
+
+// failedColumn scans the columns of the current row one by one and returns the first one that fails, or -1.
+// It is used for the drivers that don't implement ScanColumnIndexer and allow scanning the same row again,
+// such as database/sql. The destination is already partially overwritten at this point.
+func failedColumn(rows Rows, scans []interface{}) int {
+	probe := make([]interface{}, len(scans))
+	for i := range scans {
+		if scans[i] == emptyScanObj {
+			continue
+		}
+		for j := range probe {
+			probe[j] = emptyScanObj
+		}
+		probe[i] = scans[i]
+		if rows.Scan(probe...) != nil {
+			return i
+		}
+	}
+	return -1
+}
+
+// describe returns the dotted path of the field in t, prefixed by the name of t, and the field type.
+func (f *fieldPath) describe(t reflect.Type) (string, reflect.Type) {
+	var sb strings.Builder
+	sb.WriteString(t.Name())
+	if sb.Len() == 0 {
+		sb.WriteString(t.String())
+	}
+
+	for next := f; next != nil; next = next.next {
+		field := t.Field(next.idx)
+		sb.WriteByte('.')
+		sb.WriteString(field.Name)
+		t = field.Type
+	}
+	return sb.String(), t
+}
@@ -0,0 +1,125 @@
+package core
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// typedRows adds column types to sliceRows.
+type typedRows struct {
+	*sliceRows
+	types []ColumnType
+}
+
+func (r typedRows) ColumnTypes() []ColumnType {
+	return r.types
+}
+
+// indexedRows reports the failed column like pgx and can't scan a row again after a failure.
+type indexedRows struct {
+	*sliceRows
+	failed bool
+}
+
+func (r *indexedRows) Scan(dest ...interface{}) error {
+	if r.failed {
+		panic("the row is scanned again")
+	}
+	err := r.sliceRows.Scan(dest...)
+	if err != nil {
+		r.failed = true
+		return indexedError{err: err, col: 1}
+	}
+	return nil
+}
+
+func (r *indexedRows) ScanColumnIndex(err error) (int, bool) {
+	var ie indexedError
+	if errors.As(err, &ie) {
+		return ie.col, true
+	}
+	return 0, false
+}
+
+type indexedError struct {
+	err error
+	col int
+}
+
+func (e indexedError) Error() string {
+	return e.err.Error()
+}
+
+func TestScanError(t *testing.T) {
+	t.Run("struct field", func(t *testing.T) {
+		type audit struct {
+			CreatedAt time.Time `db:"created_at"`
+		}
+		type user struct {
+			ID int `db:"id"`
+			audit
+		}
+
+		rows := typedRows{
+			sliceRows: newSliceRows([]string{"id", "created_at"},
+				[]interface{}{1, time.Now()},
+				[]interface{}{2, "yesterday"},
+			),
+			types: []ColumnType{{OID: 20, Name: "int8"}, {OID: 25, Name: "text"}},
+		}
+
+		var result []user
+		slice, err := NewSlice(&result)
+		noError(t, err)
+
+		err = slice.Scan(rows, Options{})
+		var se *ScanError
+		if !errors.As(err, &se) {
+			t.Fatalf("expected *ScanError but got %v", err)
+		}
+		equal(t, 1, se.Row)
+		equal(t, "created_at", se.Column)
+		equal(t, "user.audit.CreatedAt", se.Field)
+		equal(t, reflect.TypeOf(time.Time{}), se.Type)
+		equal(t, ColumnType{OID: 25, Name: "text"}, se.ColumnType)
+		equal(t, `rows.Scan: row 1, column "created_at" (text, oid 25) into user.audit.CreatedAt (time.Time): can't scan into dest[1]`, se.Error())
+	})
+
+	t.Run("column index from the driver", func(t *testing.T) {
+		type user struct {
+			ID   int `db:"id"`
+			Name int `db:"name"`
+		}
+		rows := &indexedRows{sliceRows: newSliceRows([]string{"id", "name"}, []interface{}{1, "foo"})}
+
+		var result []user
+		slice, err := NewSlice(&result)
+		noError(t, err)
+
+		err = slice.Scan(rows, Options{})
+		var se *ScanError
+		if !errors.As(err, &se) {
+			t.Fatalf("expected *ScanError but got %v", err)
+		}
+		equal(t, "name", se.Column)
+		equal(t, "user.Name", se.Field)
+	})
+
+	t.Run("supported type", func(t *testing.T) {
+		var result int
+		row, err := NewRow(&result)
+		noError(t, err)
+
+		err = row.Scan(newSliceRows([]string{"n"}, []interface{}{"x"}), Options{})
+		var se *ScanError
+		if !errors.As(err, &se) {
+			t.Fatalf("expected *ScanError but got %v", err)
+		}
+		equal(t, 0, se.Row)
+		equal(t, "n", se.Column)
+		equal(t, "", se.Field)
+		equal(t, `rows.Scan: row 0, column "n" into int: can't scan into dest[0]`, se.Error())
+	})
+}
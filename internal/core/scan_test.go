@@ -0,0 +1,226 @@
+package core
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// sliceRows is an in-memory Rows.
+type sliceRows struct {
+	columns []string
+	values  [][]interface{}
+	idx     int
+	err     error
+}
+
+func newSliceRows(columns []string, values ...[]interface{}) *sliceRows {
+	return &sliceRows{columns: columns, values: values, idx: -1}
+}
+
+func (r *sliceRows) Next() bool {
+	r.idx++
+	return r.idx < len(r.values)
+}
+
+func (r *sliceRows) Columns() []string {
+	return r.columns
+}
+
+func (r *sliceRows) Err() error {
+	return r.err
+}
+
+func (r *sliceRows) Scan(dest ...interface{}) error {
+	row := r.values[r.idx]
+	for i, d := range dest {
+		if d == emptyScanObj {
+			continue
+		}
+		if s, ok := d.(sql.Scanner); ok {
+			if err := s.Scan(row[i]); err != nil {
+				return fmt.Errorf("can't scan into dest[%d]: %w", i, err)
+			}
+			continue
+		}
+		dv := reflect.ValueOf(d).Elem()
+		if row[i] == nil {
+			dv.Set(reflect.Zero(dv.Type()))
+			continue
+		}
+		v := reflect.ValueOf(row[i])
+		if dv.Kind() == reflect.Ptr && v.Type().ConvertibleTo(dv.Type().Elem()) {
+			dv.Set(reflect.New(dv.Type().Elem()))
+			dv = dv.Elem()
+		}
+		if !v.Type().ConvertibleTo(dv.Type()) {
+			return fmt.Errorf("can't scan into dest[%d]", i)
+		}
+		dv.Set(v.Convert(dv.Type()))
+	}
+	return nil
+}
+
+type scanTestEmbedded struct {
+	Version int `db:"version"`
+}
+
+type scanTestType struct {
+	ID   int     `db:"id"`
+	Name *string `db:"name"`
+	scanTestEmbedded
+}
+
+func TestRow(t *testing.T) {
+	t.Run("struct", func(t *testing.T) {
+		var result scanTestType
+		row, err := NewRow(&result)
+		noError(t, err)
+
+		err = row.Scan(newSliceRows([]string{"id", "extra", "version"}, []interface{}{1, "x", 3}), Options{})
+		noError(t, err)
+		equal(t, scanTestType{ID: 1, scanTestEmbedded: scanTestEmbedded{Version: 3}}, result)
+	})
+
+	t.Run("supported", func(t *testing.T) {
+		var result int
+		row, err := NewRow(&result)
+		noError(t, err)
+
+		err = row.Scan(newSliceRows([]string{"?column?"}, []interface{}{1}), Options{})
+		noError(t, err)
+		equal(t, 1, result)
+	})
+
+	t.Run("no rows", func(t *testing.T) {
+		var result int
+		row, err := NewRow(&result)
+		noError(t, err)
+		equal(t, ErrNoRows, row.Scan(newSliceRows([]string{"id"}), Options{}))
+	})
+
+	t.Run("rows error", func(t *testing.T) {
+		var result int
+		row, err := NewRow(&result)
+		noError(t, err)
+
+		rows := newSliceRows([]string{"id"})
+		rows.err = errors.New("conn closed")
+		equal(t, rows.err, row.Scan(rows, Options{}))
+	})
+
+	t.Run("more than one row", func(t *testing.T) {
+		var result int
+		row, err := NewRow(&result)
+		noError(t, err)
+		equal(t, ErrMoreThanOneRow, row.Scan(newSliceRows([]string{"id"}, []interface{}{1}, []interface{}{2}), Options{}))
+	})
+
+	t.Run("not a pointer", func(t *testing.T) {
+		_, err := NewRow(1)
+		errorContains(t, err, "must be a non nil pointer")
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		_, err := NewRow(new(chan int))
+		errorContains(t, err, "expected a struct or a pgx supported type")
+	})
+}
+
+func TestSlice(t *testing.T) {
+	rows := func() Rows {
+		return newSliceRows([]string{"id", "name"}, []interface{}{1, "foo"}, []interface{}{2, nil})
+	}
+	foo := "foo"
+
+	t.Run("values", func(t *testing.T) {
+		var result []scanTestType
+		s, err := NewSlice(&result)
+		noError(t, err)
+
+		err = s.Scan(rows(), Options{})
+		noError(t, err)
+		equal(t, []scanTestType{{ID: 1, Name: &foo}, {ID: 2}}, result)
+	})
+
+	t.Run("pointers", func(t *testing.T) {
+		var result []*scanTestType
+		s, err := NewSlice(&result)
+		noError(t, err)
+
+		err = s.Scan(rows(), Options{})
+		noError(t, err)
+		equal(t, []*scanTestType{{ID: 1, Name: &foo}, {ID: 2}}, result)
+	})
+
+	t.Run("replace", func(t *testing.T) {
+		result := []scanTestType{{ID: 10}, {ID: 11}, {ID: 12}}
+		s, err := NewSlice(&result)
+		noError(t, err)
+
+		err = s.Scan(rows(), Options{Mode: ModeReplace})
+		noError(t, err)
+		equal(t, []scanTestType{{ID: 1, Name: &foo}, {ID: 2}}, result)
+	})
+
+	t.Run("expected rows", func(t *testing.T) {
+		result := []int{10}
+		s, err := NewSlice(&result)
+		noError(t, err)
+
+		err = s.Scan(newSliceRows([]string{"id"}, []interface{}{1}), Options{ExpectedRows: 20})
+		noError(t, err)
+		equal(t, []int{10, 1}, result)
+		equal(t, 21, cap(result))
+	})
+
+	t.Run("no matches", func(t *testing.T) {
+		var result []scanTestType
+		s, err := NewSlice(&result)
+		noError(t, err)
+
+		err = s.Scan(newSliceRows([]string{"foo"}, []interface{}{1}), Options{})
+		errorContains(t, err, "have no matches to columns")
+	})
+
+	t.Run("not a slice", func(t *testing.T) {
+		_, err := NewSlice(new(int))
+		errorContains(t, err, "expected a slice but got")
+	})
+}
+
+func equal(t *testing.T, l, r interface{}) {
+	t.Helper()
+	if !reflect.DeepEqual(l, r) {
+		t.Errorf("%v not equal %v", js(l), js(r))
+	}
+}
+
+func js(v interface{}) string {
+	result, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%#v", v)
+	}
+	return string(result)
+}
+
+func noError(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func errorContains(t *testing.T, err error, str string) {
+	t.Helper()
+	if err == nil {
+		t.Fatal("error is nil")
+	}
+	if !strings.Contains(err.Error(), str) {
+		t.Fatalf("error %v does not contains %s", err, str)
+	}
+}
@@ -1,4 +1,4 @@
-package easyscan
+package core
 
 import (
 	"database/sql"
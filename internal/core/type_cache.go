@@ -0,0 +1,255 @@
+package core
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+const (
+	dbTagName           = "db"
+	sliceContainerLimit = 32
+)
+
+type fieldsContainer interface {
+	find(column string, value reflect.Value) interface{}
+	lookup(column string) (Field, bool)
+}
+
+type fieldsContainerSlice []Field
+type fieldsContainerMap map[string]Field
+
+// intrusive linked list
+type fieldPath struct {
+	idx  int
+	next *fieldPath
+}
+
+// Field is a tagged field of a struct, fields of embedded structs are flattened.
+type Field struct {
+	idx fieldPath
+	// Name is the column name, the first part of the db tag.
+	Name string
+	// Options are the comma separated parts of the db tag after the name.
+	Options []string
+	// def is the parsed default option, invalid when there is none
+	def    reflect.Value
+	defErr error
+}
+
+// Value returns the field of the struct value v.
+func (f Field) Value(v reflect.Value) reflect.Value {
+	return f.idx.field(v)
+}
+
+// Path returns the dotted path of the field in the struct type t prefixed by its name, e.g. User.Audit.CreatedAt.
+func (f Field) Path(t reflect.Type) string {
+	path, _ := f.idx.describe(t)
+	return path
+}
+
+// Type returns the Go type of the field in the struct type t.
+func (f Field) Type(t reflect.Type) reflect.Type {
+	return f.idx.structField(t).Type
+}
+
+// HasOption reports whether the db tag of the field contains option.
+func (f Field) HasOption(option string) bool {
+	for _, o := range f.Options {
+		if o == option {
+			return true
+		}
+	}
+	return false
+}
+
+func createSliceContainer(fields []Field) fieldsContainerSlice {
+	return fields
+}
+
+func (s fieldsContainerSlice) find(column string, value reflect.Value) interface{} {
+	for _, v := range s {
+		if v.Name == column {
+			return v.idx.eface(value)
+		}
+	}
+	return emptyScanObj
+}
+
+func (s fieldsContainerSlice) lookup(column string) (Field, bool) {
+	for _, v := range s {
+		if v.Name == column {
+			return v, true
+		}
+	}
+	return Field{}, false
+}
+
+func createMapContainer(fields []Field) fieldsContainerMap {
+	m := make(map[string]Field, len(fields))
+	for _, v := range fields {
+		m[v.Name] = v
+	}
+	return m
+}
+
+func (s fieldsContainerMap) find(column string, value reflect.Value) interface{} {
+	f, ok := s[column]
+	if !ok {
+		return emptyScanObj
+	}
+	return f.idx.eface(value)
+}
+
+func (s fieldsContainerMap) lookup(column string) (Field, bool) {
+	f, ok := s[column]
+	return f, ok
+}
+
+func (f *fieldPath) eface(t reflect.Value) interface{} {
+	return f.field(t).Addr().Interface()
+}
+
+func (f *fieldPath) field(t reflect.Value) reflect.Value {
+	next := f
+
+	for {
+		field := t.Field((*next).idx)
+		if next.next == nil {
+			return field
+		} else {
+			t = field
+			next = next.next
+		}
+	}
+}
+
+func (f *fieldPath) append(e *fieldPath) {
+	for f.next != nil {
+		f = f.next
+	}
+	f.next = e
+}
+
+func (f *fieldPath) copy() fieldPath {
+	result := *f
+
+	l := *f
+	r := &result
+
+	for l.next != nil {
+		next := *l.next
+
+		r.next = &next
+		r = r.next
+		l = next
+	}
+	return result
+}
+
+func extractFields(t reflect.Type) []Field {
+	fields := make([]Field, 0)
+	exploreStruct(t, &fields, nil)
+
+	return fields[:len(fields):len(fields)]
+}
+
+func exploreStruct(t reflect.Type, result *[]Field, root *fieldPath) {
+	numField := t.NumField()
+
+	for i := 0; i < numField; i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get(dbTagName)
+
+		if tag != "" {
+			sf := parseTag(tag)
+			sf.parseDefaultOption(f.Type)
+			fieldIndex := fieldPath{idx: i}
+
+			if root == nil {
+				sf.idx = fieldIndex
+			} else {
+				cp := root.copy()
+				cp.append(&fieldIndex)
+				sf.idx = cp
+			}
+
+			*result = append(*result, sf)
+			continue
+		}
+
+		//embedding here
+		if f.Anonymous && f.Type.Kind() == reflect.Struct {
+			if root == nil {
+				r := fieldPath{idx: i}
+				exploreStruct(f.Type, result, &r)
+			} else {
+				cp := root.copy()
+				cp.append(&fieldPath{idx: i})
+				exploreStruct(f.Type, result, &cp)
+			}
+		}
+	}
+}
+
+// parseTag splits a db tag into the column name and options.
+func parseTag(tag string) Field {
+	parts := strings.Split(tag, ",")
+	f := Field{Name: parts[0]}
+	if len(parts) > 1 {
+		f.Options = parts[1:]
+	}
+	return f
+}
+
+type typeInfo struct {
+	fields    []Field
+	container fieldsContainer
+	// hasTargets reports whether a field is tagged with nullzero or json or has a default value
+	hasTargets bool
+}
+
+var typeCache = new(sync.Map)
+
+func getTypeInfo(t reflect.Type) *typeInfo {
+	cached, ok := typeCache.Load(t)
+	if ok {
+		return cached.(*typeInfo)
+	}
+
+	fields := extractFields(t)
+	result := &typeInfo{fields: fields}
+	for _, f := range fields {
+		if f.HasOption(TagNullZero) || f.HasDefault() || f.HasOption(TagJSON) {
+			result.hasTargets = true
+		}
+	}
+	if len(fields) > sliceContainerLimit {
+		result.container = createMapContainer(fields)
+	} else {
+		result.container = createSliceContainer(fields)
+	}
+
+	typeCache.Store(t, result)
+	return result
+}
+
+func getTaggedFields(t reflect.Type) fieldsContainer {
+	return getTypeInfo(t).container
+}
+
+// Fields returns the tagged fields of the struct type t in declaration order.
+// The result is cached and must not be modified.
+func Fields(t reflect.Type) []Field {
+	return getTypeInfo(t).fields
+}
+
+// FieldByTag returns the field of the struct value v tagged with name.
+// Fields of embedded structs are found the same way as for scanning.
+func FieldByTag(v reflect.Value, name string) (reflect.Value, bool) {
+	f, ok := getTaggedFields(v.Type()).lookup(name)
+	if !ok {
+		return reflect.Value{}, false
+	}
+	return f.Value(v), true
+}
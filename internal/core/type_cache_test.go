@@ -1,4 +1,4 @@
-package easyscan
+package core
 
 import (
 	"fmt"
@@ -56,7 +56,7 @@ func Test_getStructDBTags(t *testing.T) {
 						tv := reflect.New(tt).Elem()
 						tags := getTaggedFields(tt)
 						for f := 0; f < 5; f++ {
-							eface := tags.find([]byte(fmt.Sprintf("Field%d", f)), tv)
+							eface := tags.find(fmt.Sprintf("Field%d", f), tv)
 							v, ok := eface.(*int)
 							equal(t, true, ok)
 
@@ -69,7 +69,7 @@ func Test_getStructDBTags(t *testing.T) {
 						btv := reflect.New(btt).Elem()
 						bigType := getTaggedFields(btt)
 						for f := 0; f < 21; f++ {
-							eface := bigType.find([]byte(fmt.Sprintf("Field%d", f)), btv)
+							eface := bigType.find(fmt.Sprintf("Field%d", f), btv)
 							v, ok := eface.(*int)
 							equal(t, true, ok)
 
@@ -86,13 +86,59 @@ func Test_getStructDBTags(t *testing.T) {
 	t.Run("not found", func(t *testing.T) {
 		tt := new(testType)
 		tags := getTaggedFields(reflect.TypeOf(*tt))
-		f := tags.find([]byte("test_123"), reflect.ValueOf(*tt))
+		f := tags.find("test_123", reflect.ValueOf(*tt))
 		equal(t, true, emptyScanObj == f.(emptyScan))
 
 		btt := new(bigTestType)
 		tags = getTaggedFields(reflect.TypeOf(*btt))
-		f = tags.find([]byte("test_123"), reflect.ValueOf(*btt))
+		f = tags.find("test_123", reflect.ValueOf(*btt))
 		equal(t, true, emptyScanObj == f.(emptyScan))
 	})
 
 }
+
+func TestFieldByTag(t *testing.T) {
+	type Embedded struct {
+		Version int `db:"version"`
+	}
+	type testType struct {
+		ID int `db:"id"`
+		Embedded
+	}
+
+	v := reflect.ValueOf(testType{ID: 1, Embedded: Embedded{Version: 2}})
+
+	f, ok := FieldByTag(v, "version")
+	equal(t, true, ok)
+	equal(t, 2, f.Interface())
+
+	f, ok = FieldByTag(v, "id")
+	equal(t, true, ok)
+	equal(t, 1, f.Interface())
+
+	_, ok = FieldByTag(v, "missing")
+	equal(t, false, ok)
+}
+
+func TestFields(t *testing.T) {
+	type Embedded struct {
+		CreatedAt int `db:"created_at,readonly"`
+	}
+	type testType struct {
+		ID   int `db:"id,generated,other"`
+		Name int `db:"name"`
+		Embedded
+	}
+
+	fields := Fields(reflect.TypeOf(testType{}))
+	equal(t, 3, len(fields))
+
+	equal(t, "id", fields[0].Name)
+	equal(t, []string{"generated", "other"}, fields[0].Options)
+	equal(t, true, fields[0].HasOption("generated"))
+	equal(t, false, fields[1].HasOption("generated"))
+
+	equal(t, "created_at", fields[2].Name)
+	equal(t, true, fields[2].HasOption("readonly"))
+	equal(t, 3, fields[2].Value(reflect.ValueOf(testType{Embedded: Embedded{CreatedAt: 3}})).Interface())
+}
@@ -0,0 +1,262 @@
+package core
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// OIDs of the built-in Postgres types, they are fixed by the catalog.
+const (
+	oidBool        = 16
+	oidBytea       = 17
+	oidName        = 19
+	oidInt8        = 20
+	oidInt2        = 21
+	oidInt4        = 23
+	oidText        = 25
+	oidOID         = 26
+	oidJSON        = 114
+	oidFloat4      = 700
+	oidFloat8      = 701
+	oidBPChar      = 1042
+	oidVarchar     = 1043
+	oidDate        = 1082
+	oidTime        = 1083
+	oidTimestamp   = 1114
+	oidTimestamptz = 1184
+	oidInterval    = 1186
+	oidNumeric     = 1700
+	oidUUID        = 2950
+	oidJSONB       = 3802
+
+	oidBoolArray        = 1000
+	oidInt2Array        = 1005
+	oidInt4Array        = 1007
+	oidTextArray        = 1009
+	oidVarcharArray     = 1015
+	oidInt8Array        = 1016
+	oidFloat4Array      = 1021
+	oidFloat8Array      = 1022
+	oidTimestampArray   = 1115
+	oidTimestamptzArray = 1185
+	oidNumericArray     = 1231
+	oidUUIDArray        = 2951
+)
+
+type kinds []reflect.Kind
+
+var (
+	stringKinds = kinds{reflect.String}
+	listKinds   = kinds{reflect.Slice, reflect.Array}
+	jsonKinds   = kinds{
+		reflect.String, reflect.Bool, reflect.Slice, reflect.Array,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64,
+	}
+)
+
+// compatibleKinds lists the kinds of Go types a column of a built-in type scans into.
+// Kinds missing from the table are never checked, time.Time is handled separately.
+var compatibleKinds = map[uint32]kinds{
+	oidBool:        {reflect.Bool},
+	oidInt2:        {reflect.Int, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64},
+	oidInt4:        {reflect.Int, reflect.Int32, reflect.Int64, reflect.Uint32, reflect.Uint64, reflect.Float64},
+	oidInt8:        {reflect.Int, reflect.Int64, reflect.Uint64},
+	oidOID:         {reflect.Int, reflect.Int64, reflect.Uint32, reflect.Uint64},
+	oidFloat4:      {reflect.Float32, reflect.Float64},
+	oidFloat8:      {reflect.Float64},
+	oidNumeric:     {reflect.Float32, reflect.Float64, reflect.String},
+	oidText:        stringKinds,
+	oidVarchar:     stringKinds,
+	oidBPChar:      stringKinds,
+	oidName:        stringKinds,
+	oidUUID:        {reflect.String, reflect.Array},
+	oidBytea:       {},
+	oidDate:        {},
+	oidTime:        {reflect.String},
+	oidTimestamp:   {},
+	oidTimestamptz: {},
+	oidInterval:    {reflect.Int64},
+	oidJSON:        jsonKinds,
+	oidJSONB:       jsonKinds,
+
+	oidBoolArray:        listKinds,
+	oidInt2Array:        listKinds,
+	oidInt4Array:        listKinds,
+	oidInt8Array:        listKinds,
+	oidTextArray:        listKinds,
+	oidVarcharArray:     listKinds,
+	oidFloat4Array:      listKinds,
+	oidFloat8Array:      listKinds,
+	oidTimestampArray:   listKinds,
+	oidTimestamptzArray: listKinds,
+	oidNumericArray:     listKinds,
+	oidUUIDArray:        listKinds,
+}
+
+// builtinOIDs resolves the lowercase names of the built-in types, as reported by database/sql drivers, into OIDs.
+var builtinOIDs = map[string]uint32{
+	"bool":        oidBool,
+	"bytea":       oidBytea,
+	"name":        oidName,
+	"int8":        oidInt8,
+	"int2":        oidInt2,
+	"int4":        oidInt4,
+	"text":        oidText,
+	"oid":         oidOID,
+	"json":        oidJSON,
+	"float4":      oidFloat4,
+	"float8":      oidFloat8,
+	"bpchar":      oidBPChar,
+	"varchar":     oidVarchar,
+	"date":        oidDate,
+	"time":        oidTime,
+	"timestamp":   oidTimestamp,
+	"timestamptz": oidTimestamptz,
+	"interval":    oidInterval,
+	"numeric":     oidNumeric,
+	"uuid":        oidUUID,
+	"jsonb":       oidJSONB,
+
+	"_bool":        oidBoolArray,
+	"_int2":        oidInt2Array,
+	"_int4":        oidInt4Array,
+	"_text":        oidTextArray,
+	"_varchar":     oidVarcharArray,
+	"_int8":        oidInt8Array,
+	"_float4":      oidFloat4Array,
+	"_float8":      oidFloat8Array,
+	"_timestamp":   oidTimestampArray,
+	"_timestamptz": oidTimestamptzArray,
+	"_numeric":     oidNumericArray,
+	"_uuid":        oidUUIDArray,
+}
+
+// typeOID returns the OID of ct, looking it up by the name when the driver reports no OID.
+func typeOID(ct ColumnType) uint32 {
+	if ct.OID != 0 {
+		return ct.OID
+	}
+	return builtinOIDs[strings.ToLower(ct.Name)]
+}
+
+// timeOIDs are the types that scan into time.Time.
+var timeOIDs = map[uint32]bool{oidDate: true, oidTimestamp: true, oidTimestamptz: true}
+
+// textOIDs are the types that also scan into []byte.
+var textOIDs = map[uint32]bool{oidBytea: true, oidText: true, oidVarchar: true, oidBPChar: true, oidName: true, oidJSON: true, oidJSONB: true}
+
+// TypeMismatch is a column whose database type can't be scanned into the Go type of its destination.
+type TypeMismatch struct {
+	Column     string
+	ColumnType ColumnType
+	// Field is the path of the destination struct field, empty when the destination isn't a struct.
+	Field string
+	Type  reflect.Type
+}
+
+// TypeMismatchError lists every incompatible column of a result.
+type TypeMismatchError struct {
+	Mismatches []TypeMismatch
+}
+
+func (e *TypeMismatchError) Error() string {
+	var sb strings.Builder
+	sb.WriteString("incompatible column types: ")
+	for i, m := range e.Mismatches {
+		if i > 0 {
+			sb.WriteString("; ")
+		}
+		name := m.ColumnType.Name
+		if name == "" {
+			name = fmt.Sprintf("oid %d", m.ColumnType.OID)
+		}
+		fmt.Fprintf(&sb, "column %q (%s) into ", m.Column, name)
+		if m.Field != "" {
+			fmt.Fprintf(&sb, "%s (%s)", m.Field, m.Type)
+		} else {
+			sb.WriteString(m.Type.String())
+		}
+	}
+	return sb.String()
+}
+
+// checkTypes compares the column types of rows with the destination types
+// and returns a *TypeMismatchError with every incompatible pair.
+// Nothing is checked when rows don't report column types,
+// a type without an OID, as reported by database/sql, is matched by its Postgres name.
+func checkTypes(rows Rows, t reflect.Type, mapped bool) error {
+	ct, ok := rows.(ColumnTyper)
+	if !ok {
+		return nil
+	}
+	types := ct.ColumnTypes()
+	columns := rows.Columns()
+
+	var mismatches []TypeMismatch
+	for i, column := range columns {
+		if i >= len(types) {
+			break
+		}
+
+		m := TypeMismatch{Column: column, ColumnType: types[i], Type: t}
+		isJSON := false
+		if mapped {
+			f, ok := getTaggedFields(t).lookup(column)
+			if !ok {
+				continue
+			}
+			m.Field, m.Type = f.idx.describe(t)
+			isJSON = f.HasOption(TagJSON)
+		} else if i > 0 {
+			break
+		}
+
+		compatible := CompatibleType(typeOID(m.ColumnType), m.Type)
+		if isJSON {
+			compatible = IsJSONType(m.ColumnType)
+		}
+		if !compatible {
+			mismatches = append(mismatches, m)
+		}
+	}
+
+	if len(mismatches) > 0 {
+		return &TypeMismatchError{Mismatches: mismatches}
+	}
+	return nil
+}
+
+// CompatibleType reports whether a column of the built-in type oid can be scanned into t.
+// Unknown types, sql.Scanner implementations and kinds outside of the table are reported as compatible.
+func CompatibleType(oid uint32, t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	allowed, ok := compatibleKinds[oid]
+	if !ok || t.Kind() == reflect.Interface || implementsScanner(t) {
+		return true
+	}
+
+	if t == timeType {
+		return timeOIDs[oid]
+	}
+	if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 && textOIDs[oid] {
+		return true
+	}
+
+	// structs and maps may have custom decoders, e.g. pgtype values, they are left to the driver
+	if t.Kind() == reflect.Struct || t.Kind() == reflect.Map {
+		return true
+	}
+
+	for _, k := range allowed {
+		if t.Kind() == k {
+			return true
+		}
+	}
+	return false
+}
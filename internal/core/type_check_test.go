@@ -0,0 +1,115 @@
+package core
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCompatibleType(t *testing.T) {
+	cases := []struct {
+		oid      uint32
+		value    interface{}
+		expected bool
+	}{
+		{oidInt4, int32(0), true},
+		{oidInt4, new(int64), true},
+		{oidInt8, int32(0), false},
+		{oidNumeric, int32(0), false},
+		{oidNumeric, float64(0), true},
+		{oidTimestamptz, "", false},
+		{oidTimestamptz, time.Time{}, true},
+		{oidText, time.Time{}, false},
+		{oidText, []byte(nil), true},
+		{oidBytea, "", false},
+		{oidInterval, time.Duration(0), true},
+		{oidInt4Array, []int32(nil), true},
+		{oidInt4Array, 0, false},
+		{oidJSONB, map[string]interface{}{}, true},
+		{oidJSONB, int64(0), true},
+		{oidJSON, float32(0), true},
+		{oidJSON, time.Time{}, false},
+		{oidBool, sql.NullString{}, true},
+		// unknown types are left to the driver
+		{100000, 0, true},
+	}
+
+	for _, c := range cases {
+		typ := reflect.TypeOf(c.value)
+		if actual := CompatibleType(c.oid, typ); actual != c.expected {
+			t.Errorf("oid %d into %s: expected %v but got %v", c.oid, typ, c.expected, actual)
+		}
+	}
+}
+
+func TestCheckTypes(t *testing.T) {
+	type account struct {
+		ID      int32     `db:"id"`
+		Balance int32     `db:"balance"`
+		Opened  string    `db:"opened"`
+		Closed  time.Time `db:"closed"`
+	}
+
+	rows := func() typedRows {
+		return typedRows{
+			sliceRows: newSliceRows([]string{"id", "balance", "opened", "closed"}, []interface{}{1, 2, "3", time.Now()}),
+			types: []ColumnType{
+				{OID: oidInt4, Name: "int4"},
+				{OID: oidNumeric, Name: "numeric"},
+				{OID: oidTimestamptz, Name: "timestamptz"},
+				{OID: oidTimestamptz, Name: "timestamptz"},
+			},
+		}
+	}
+
+	t.Run("all mismatches", func(t *testing.T) {
+		var result []account
+		s, err := NewSlice(&result)
+		noError(t, err)
+
+		err = s.Scan(rows(), Options{CheckTypes: true})
+		var tme *TypeMismatchError
+		if !errors.As(err, &tme) {
+			t.Fatalf("expected *TypeMismatchError but got %v", err)
+		}
+		equal(t, 2, len(tme.Mismatches))
+		equal(t, `incompatible column types: column "balance" (numeric) into account.Balance (int32); column "opened" (timestamptz) into account.Opened (string)`, err.Error())
+		equal(t, 0, len(result))
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		var result []account
+		s, err := NewSlice(&result)
+		noError(t, err)
+
+		err = s.Scan(rows(), Options{})
+		noError(t, err)
+		equal(t, 1, len(result))
+	})
+
+	t.Run("type names", func(t *testing.T) {
+		var result []account
+		s, err := NewSlice(&result)
+		noError(t, err)
+
+		err = s.Scan(typedRows{
+			sliceRows: newSliceRows([]string{"id", "balance", "opened", "closed"}, []interface{}{1, 2, "3", time.Now()}),
+			types:     []ColumnType{{Name: "int4"}, {Name: "NUMERIC"}, {Name: "unknown"}, {Name: "timestamptz"}},
+		}, Options{CheckTypes: true})
+		equal(t, `incompatible column types: column "balance" (NUMERIC) into account.Balance (int32)`, err.Error())
+	})
+
+	t.Run("supported type", func(t *testing.T) {
+		var result int32
+		row, err := NewRow(&result)
+		noError(t, err)
+
+		err = row.Scan(typedRows{
+			sliceRows: newSliceRows([]string{"n"}, []interface{}{1}),
+			types:     []ColumnType{{OID: oidNumeric, Name: "numeric"}},
+		}, Options{CheckTypes: true})
+		equal(t, `incompatible column types: column "n" (numeric) into int32`, err.Error())
+	})
+}
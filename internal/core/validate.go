@@ -0,0 +1,116 @@
+package core
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Problem is a mapping problem of a destination type.
+type Problem struct {
+	Type reflect.Type
+	// Field is the path of the field, e.g. User.Audit.CreatedAt, empty for problems of the whole type.
+	Field   string
+	Message string
+}
+
+// MappingError lists every mapping problem found in the validated types.
+type MappingError struct {
+	Problems []Problem
+}
+
+func (e *MappingError) Error() string {
+	var sb strings.Builder
+	sb.WriteString("invalid destination types:")
+	for _, p := range e.Problems {
+		sb.WriteString("\n\t")
+		if p.Field != "" {
+			sb.WriteString(p.Field)
+		} else {
+			sb.WriteString(p.Type.String())
+		}
+		sb.WriteString(": ")
+		sb.WriteString(p.Message)
+	}
+	return sb.String()
+}
+
+// unsupportedKinds can't hold a value of any column.
+var unsupportedKinds = map[reflect.Kind]bool{
+	reflect.Func:          true,
+	reflect.Chan:          true,
+	reflect.UnsafePointer: true,
+	reflect.Complex64:     true,
+	reflect.Complex128:    true,
+	reflect.Uintptr:       true,
+}
+
+// ValidateType returns the mapping problems of the struct type t: tagged unexported fields, duplicate
+// and empty column names, fields of unsupported kinds, invalid default values and tag options missing from knownOptions.
+// The type cache isn't used or modified.
+func ValidateType(t reflect.Type, knownOptions map[string]bool) []Problem {
+	if t.Kind() != reflect.Struct {
+		return []Problem{{Type: t, Message: fmt.Sprintf("expected a struct but got %s", t.Kind())}}
+	}
+
+	var problems []Problem
+	add := func(field, format string, args ...interface{}) {
+		problems = append(problems, Problem{Type: t, Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	columns := make(map[string]string)
+	for _, f := range extractFields(t) {
+		path, ft := f.idx.describe(t)
+		sf := f.idx.structField(t)
+
+		if !sf.IsExported() {
+			add(path, "tagged field is unexported")
+		}
+
+		if f.Name == "" {
+			add(path, "column name is empty")
+		} else if other, ok := columns[f.Name]; ok {
+			add(path, "column %q is already mapped to %s", f.Name, other)
+		} else {
+			columns[f.Name] = path
+		}
+
+		base := ft
+		for base.Kind() == reflect.Ptr || base.Kind() == reflect.Slice || base.Kind() == reflect.Array {
+			base = base.Elem()
+		}
+		if unsupportedKinds[base.Kind()] {
+			add(path, "unsupported type %s", ft)
+		}
+
+		if err := f.DefaultError(); err != nil {
+			add(path, "%v", err)
+		}
+
+		for _, o := range f.Options {
+			if !knownOptions[optionName(o)] {
+				add(path, "unknown tag option %q", o)
+			}
+		}
+	}
+
+	return problems
+}
+
+// optionName returns the name of a tag option, the part before "=" for options with a value.
+func optionName(option string) string {
+	if i := strings.IndexByte(option, '='); i >= 0 {
+		return option[:i]
+	}
+	return option
+}
+
+// structField returns the last struct field of the path in t.
+func (f *fieldPath) structField(t reflect.Type) reflect.StructField {
+	var sf reflect.StructField
+	for next := f; next != nil; next = next.next {
+		sf = t.Field(next.idx)
+		t = sf.Type
+	}
+	return sf
+}
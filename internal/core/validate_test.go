@@ -0,0 +1,47 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestValidateType(t *testing.T) {
+	type audit struct {
+		ID int `db:"id"`
+	}
+	type broken struct {
+		ID       int         `db:"id"`
+		name     string      `db:"name"`
+		Empty    string      `db:",generated"`
+		Callback func()      `db:"callback"`
+		Numbers  []complex64 `db:"numbers"`
+		Typo     string      `db:"typo,genrated"`
+		audit
+	}
+	type valid struct {
+		ID   int    `db:"id,generated"`
+		Name string `db:"name"`
+	}
+
+	known := map[string]bool{"generated": true}
+
+	problems := ValidateType(reflect.TypeOf(broken{}), known)
+	messages := make([]string, len(problems))
+	for i, p := range problems {
+		messages[i] = p.Field + ": " + p.Message
+	}
+	equal(t, []string{
+		"broken.name: tagged field is unexported",
+		"broken.Empty: column name is empty",
+		"broken.Callback: unsupported type func()",
+		"broken.Numbers: unsupported type []complex64",
+		`broken.Typo: unknown tag option "genrated"`,
+		`broken.audit.ID: column "id" is already mapped to broken.ID`,
+	}, messages)
+
+	equal(t, 0, len(ValidateType(reflect.TypeOf(valid{}), known)))
+	equal(t, 1, len(ValidateType(reflect.TypeOf(1), known)))
+
+	_, cached := typeCache.Load(reflect.TypeOf(broken{}))
+	equal(t, false, cached)
+}
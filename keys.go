@@ -0,0 +1,197 @@
+package easyscan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+
+	"github.com/popovpsk/easyscan/internal/core"
+)
+
+// ErrMissingKeys is matched by a *MissingKeysError with errors.Is.
+var ErrMissingKeys = errors.New("keys not found")
+
+// MissingKeysError is returned by GetByKeys when some keys have no row.
+type MissingKeysError struct {
+	// Indexes are the sorted positions of the missing keys in the keys slice.
+	Indexes []int
+	// Keys are the missing keys in the order of Indexes.
+	Keys []interface{}
+}
+
+func (e *MissingKeysError) Error() string {
+	return fmt.Sprintf("%d keys not found: %v", len(e.Keys), e.Keys)
+}
+
+func (e *MissingKeysError) Is(target error) bool {
+	return target == ErrMissingKeys
+}
+
+// Missing reports whether the key at position i of the keys slice has no row.
+func (e *MissingKeysError) Missing(i int) bool {
+	idx := sort.SearchInts(e.Indexes, i)
+	return idx < len(e.Indexes) && e.Indexes[idx] == i
+}
+
+// GetByKeys runs query once with keys as $1, e.g. "SELECT * FROM users WHERE id = ANY($1)",
+// and fills dest, a pointer to a slice of tagged structs, with one element per key in the order of keys.
+// keyTag is the db tag of the field holding the key, args are passed as $2 and so on.
+// A pointer key field is dereferenced, and keys of another integer or float type are converted to the field type.
+//
+// When some keys have no row dest still has an element for every key, a zero value or nil,
+// and a *MissingKeysError describes them.
+func GetByKeys(ctx context.Context, conn Querier, dest interface{}, query string, keys interface{}, keyTag string, args ...interface{}) error {
+	rowType, err := sliceElemStruct(dest)
+	if err != nil {
+		return err
+	}
+
+	kv := reflect.ValueOf(keys)
+	if kv.Kind() != reflect.Slice && kv.Kind() != reflect.Array {
+		return fmt.Errorf("keys must be a slice or an array but got %T", keys)
+	}
+
+	var keyField core.Field
+	found := false
+	for _, f := range core.Fields(rowType) {
+		if f.Name == keyTag {
+			keyField, found = f, true
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("%s has no field tagged %q", rowType, keyTag)
+	}
+	keyType := keyField.Value(reflect.New(rowType).Elem()).Type()
+	if keyType.Kind() == reflect.Ptr {
+		keyType = keyType.Elem()
+	}
+	if !keyType.Comparable() {
+		return fmt.Errorf("%s field %q can't be used as a key", keyType, keyTag)
+	}
+	if elem := kv.Type().Elem(); elem.Kind() != reflect.Interface && !keyCompatible(elem, keyType) {
+		return fmt.Errorf("%s keys can't be compared with %s field %q", elem, keyType, keyTag)
+	}
+
+	sliceType := reflect.TypeOf(dest).Elem()
+	rowsPtr := reflect.New(sliceType)
+	err = Select(ctx, conn, rowsPtr.Interface(), query, append([]interface{}{keys}, args...)...)
+	if err != nil {
+		return err
+	}
+
+	rows := rowsPtr.Elem()
+	index := make(map[interface{}]int, rows.Len())
+	for i := 0; i < rows.Len(); i++ {
+		row := reflect.Indirect(rows.Index(i))
+		kf := keyField.Value(row)
+		if kf.Kind() == reflect.Ptr {
+			if kf.IsNil() {
+				continue
+			}
+			kf = kf.Elem()
+		}
+		k := kf.Interface()
+		if _, ok := index[k]; ok {
+			return fmt.Errorf("key %v has more than one row", k)
+		}
+		index[k] = i
+	}
+
+	result := reflect.MakeSlice(sliceType, kv.Len(), kv.Len())
+	var missing *MissingKeysError
+	for i := 0; i < kv.Len(); i++ {
+		k := kv.Index(i)
+		if k.Kind() == reflect.Interface {
+			k = k.Elem()
+		}
+		if !k.IsValid() || !keyCompatible(k.Type(), keyType) {
+			return fmt.Errorf("key %v can't be compared with %s field %q", kv.Index(i).Interface(), keyType, keyTag)
+		}
+
+		k, ok := convertKey(k, keyType)
+		idx, found := 0, false
+		if ok {
+			idx, found = index[k.Interface()]
+		}
+		if !found {
+			if missing == nil {
+				missing = &MissingKeysError{}
+			}
+			missing.Indexes = append(missing.Indexes, i)
+			missing.Keys = append(missing.Keys, kv.Index(i).Interface())
+			continue
+		}
+		result.Index(i).Set(rows.Index(idx))
+	}
+
+	reflect.ValueOf(dest).Elem().Set(result)
+	if missing != nil {
+		return missing
+	}
+	return nil
+}
+
+// keyCompatible reports whether keys of type from can be looked up among the values of a field of type to.
+// Numbers are converted only within the same kind, integers or floats, so that an int key is never turned into a string.
+func keyCompatible(from, to reflect.Type) bool {
+	switch {
+	case from == to || from.AssignableTo(to):
+		return true
+	case isInt(from.Kind()) && isInt(to.Kind()):
+		return true
+	case isFloat(from.Kind()) && isFloat(to.Kind()):
+		return true
+	case from.Kind() == reflect.String && to.Kind() == reflect.String:
+		return true
+	}
+	return false
+}
+
+// convertKey converts k, which is keyCompatible with t, to t.
+// It returns false when the value doesn't fit into t, so no row can have it.
+func convertKey(k reflect.Value, t reflect.Type) (reflect.Value, bool) {
+	if k.Type() == t || k.Type().AssignableTo(t) {
+		return k, true
+	}
+
+	zero := reflect.New(t).Elem()
+	switch {
+	case isSigned(k.Kind()) && isSigned(t.Kind()):
+		if zero.OverflowInt(k.Int()) {
+			return reflect.Value{}, false
+		}
+	case isSigned(k.Kind()):
+		if k.Int() < 0 || zero.OverflowUint(uint64(k.Int())) {
+			return reflect.Value{}, false
+		}
+	case isInt(k.Kind()) && isSigned(t.Kind()):
+		if k.Uint() > math.MaxInt64 || zero.OverflowInt(int64(k.Uint())) {
+			return reflect.Value{}, false
+		}
+	case isInt(k.Kind()):
+		if zero.OverflowUint(k.Uint()) {
+			return reflect.Value{}, false
+		}
+	case isFloat(k.Kind()):
+		if zero.OverflowFloat(k.Float()) {
+			return reflect.Value{}, false
+		}
+	}
+	return k.Convert(t), true
+}
+
+func isSigned(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Int64
+}
+
+func isInt(k reflect.Kind) bool {
+	return isSigned(k) || k >= reflect.Uint && k <= reflect.Uintptr
+}
+
+func isFloat(k reflect.Kind) bool {
+	return k == reflect.Float32 || k == reflect.Float64
+}
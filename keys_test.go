@@ -0,0 +1,138 @@
+package easyscan
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type keyedUser struct {
+	ID   int64  `db:"id"`
+	Name string `db:"name"`
+}
+
+func TestGetByKeysOrder(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("ordered by keys", func(t *testing.T) {
+		q := &captureQuerier{rows: newFakeRows([]string{"id", "name"},
+			[]interface{}{int64(3), "c"},
+			[]interface{}{int64(1), "a"},
+		)}
+		var result []keyedUser
+		err := GetByKeys(ctx, q, &result, "SELECT * FROM users WHERE id = ANY($1)", []int{1, 3, 1}, "id")
+		noError(t, err)
+		equal(t, []interface{}{[]int{1, 3, 1}}, q.args)
+		equal(t, []keyedUser{{1, "a"}, {3, "c"}, {1, "a"}}, result)
+	})
+
+	t.Run("missing keys", func(t *testing.T) {
+		q := &captureQuerier{rows: newFakeRows([]string{"id", "name"}, []interface{}{int64(2), "b"})}
+		var result []*keyedUser
+		err := GetByKeys(ctx, q, &result, "SELECT * FROM users WHERE id = ANY($1) AND name <> $2", []int64{1, 2, 3}, "id", "x")
+		notNilError(t, err)
+		equal(t, []interface{}{[]int64{1, 2, 3}, "x"}, q.args)
+		equal(t, true, errors.Is(err, ErrMissingKeys))
+
+		var missing *MissingKeysError
+		equal(t, true, errors.As(err, &missing))
+		equal(t, []int{0, 2}, missing.Indexes)
+		equal(t, []interface{}{int64(1), int64(3)}, missing.Keys)
+		equal(t, true, missing.Missing(0))
+		equal(t, false, missing.Missing(1))
+		equal(t, true, missing.Missing(2))
+
+		equal(t, 3, len(result))
+		equal(t, (*keyedUser)(nil), result[0])
+		equal(t, &keyedUser{2, "b"}, result[1])
+		equal(t, (*keyedUser)(nil), result[2])
+	})
+
+	t.Run("duplicate rows", func(t *testing.T) {
+		q := &captureQuerier{rows: newFakeRows([]string{"id"}, []interface{}{int64(1)}, []interface{}{int64(1)})}
+		var result []keyedUser
+		err := GetByKeys(ctx, q, &result, "SELECT id FROM users WHERE id = ANY($1)", []int64{1}, "id")
+		errorContains(t, err, "more than one row")
+	})
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		var result []keyedUser
+		err := GetByKeys(ctx, &captureQuerier{}, &result, "", 1, "id")
+		errorContains(t, err, "keys must be a slice")
+
+		err = GetByKeys(ctx, &captureQuerier{}, &result, "", []int{1}, "missing")
+		errorContains(t, err, `no field tagged "missing"`)
+
+		q := &captureQuerier{rows: newFakeRows([]string{"id"}, []interface{}{int64(1)})}
+		err = GetByKeys(ctx, q, &result, "", []string{"1"}, "id")
+		errorContains(t, err, "can't be compared")
+		equal(t, []interface{}(nil), q.args)
+
+		err = GetByKeys(ctx, q, &result, "", []interface{}{int64(1), "1"}, "id")
+		errorContains(t, err, `key 1 can't be compared`)
+	})
+
+	t.Run("int keys for a string field", func(t *testing.T) {
+		var result []struct {
+			Code string `db:"code"`
+		}
+		q := &captureQuerier{rows: newFakeRows([]string{"code"}, []interface{}{"A"})}
+		err := GetByKeys(ctx, q, &result, "", []int{65}, "code")
+		errorContains(t, err, `int keys can't be compared with string field "code"`)
+	})
+
+	t.Run("not comparable key field", func(t *testing.T) {
+		var result []struct {
+			Hash []byte `db:"hash"`
+		}
+		err := GetByKeys(ctx, &captureQuerier{}, &result, "", [][]byte{{1}}, "hash")
+		errorContains(t, err, `[]uint8 field "hash" can't be used as a key`)
+	})
+
+	t.Run("pointer key field", func(t *testing.T) {
+		type user struct {
+			ID   *int64 `db:"id"`
+			Name string `db:"name"`
+		}
+		q := &captureQuerier{rows: newFakeRows([]string{"id", "name"},
+			[]interface{}{int64(2), "b"},
+			[]interface{}{nil, "null"},
+		)}
+		var result []user
+		err := GetByKeys(ctx, q, &result, "", []int{2, 1}, "id")
+		equal(t, true, errors.Is(err, ErrMissingKeys))
+		equal(t, "b", result[0].Name)
+		equal(t, user{}, result[1])
+	})
+
+	t.Run("keys out of range", func(t *testing.T) {
+		type user struct {
+			ID int32 `db:"id"`
+		}
+		q := &captureQuerier{rows: newFakeRows([]string{"id"}, []interface{}{int32(1)})}
+		var result []user
+		err := GetByKeys(ctx, q, &result, "", []int64{1<<32 + 1, 1, -1 << 40}, "id")
+		var missing *MissingKeysError
+		equal(t, true, errors.As(err, &missing))
+		equal(t, []int{0, 2}, missing.Indexes)
+		equal(t, user{1}, result[1])
+	})
+}
+
+func TestGetByKeys(t *testing.T) {
+	ctx := context.Background()
+	pool, err := pgxpool.Connect(ctx, connString)
+	noError(t, err)
+	defer pool.Close()
+
+	var result []keyedUser
+	err = GetByKeys(ctx, pool, &result,
+		"SELECT id, 'user' || id AS name FROM generate_series(1, 5) AS id WHERE id = ANY($1)", []int64{4, 9, 2}, "id")
+
+	var missing *MissingKeysError
+	equal(t, true, errors.As(err, &missing))
+	equal(t, []int{1}, missing.Indexes)
+	equal(t, []keyedUser{{4, "user4"}, {}, {2, "user2"}}, result)
+}
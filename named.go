@@ -0,0 +1,218 @@
+package easyscan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/jackc/pgconn"
+
+	"github.com/popovpsk/easyscan/internal/core"
+)
+
+// namedQuery is a query with :name and @name placeholders rewritten into $N.
+type namedQuery struct {
+	sql string
+	// names[i] is bound to $i+1
+	names []string
+}
+
+var namedCache = new(sync.Map)
+
+// SelectNamed is Select for a query with :name or @name placeholders.
+// The values are taken from arg, which is a struct tagged with db tags, a pointer to it, or a map with string keys.
+func SelectNamed(ctx context.Context, conn Querier, dest interface{}, query string, arg interface{}, opts ...Option) error {
+	sql, args, err := BindNamed(query, arg)
+	if err != nil {
+		return err
+	}
+	return Select(ctx, conn, dest, sql, appendOptions(args, opts)...)
+}
+
+// GetNamed is Get for a query with :name or @name placeholders.
+func GetNamed(ctx context.Context, conn Querier, dest interface{}, query string, arg interface{}, opts ...Option) error {
+	sql, args, err := BindNamed(query, arg)
+	if err != nil {
+		return err
+	}
+	return Get(ctx, conn, dest, sql, appendOptions(args, opts)...)
+}
+
+// ExecNamed executes a query with :name or @name placeholders.
+func ExecNamed(ctx context.Context, conn Execer, query string, arg interface{}) (pgconn.CommandTag, error) {
+	if conn == nil {
+		return nil, errors.New("conn is nil")
+	}
+	conn = txFromContext(ctx, conn)
+
+	sql, args, err := BindNamed(query, arg)
+	if err != nil {
+		return nil, err
+	}
+
+	tag, err := conn.Exec(ctx, sql, args...)
+	if err != nil {
+		return nil, wrapPgError(fmt.Errorf("exec: %w", err))
+	}
+	return tag, nil
+}
+
+// BindNamed rewrites the placeholders of query into $N and returns the matching arguments.
+// String literals, quoted identifiers, comments, dollar-quoted strings and :: casts are left untouched.
+func BindNamed(query string, arg interface{}) (string, []interface{}, error) {
+	nq, err := compileNamed(query)
+	if err != nil {
+		return "", nil, err
+	}
+
+	args, err := namedArgs(nq.names, arg)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return nq.sql, args, nil
+}
+
+func appendOptions(args []interface{}, opts []Option) []interface{} {
+	for _, o := range opts {
+		args = append(args, o)
+	}
+	return args
+}
+
+func compileNamed(query string) (*namedQuery, error) {
+	cached, ok := namedCache.Load(query)
+	if ok {
+		return cached.(*namedQuery), nil
+	}
+
+	nq, err := parseNamed(query)
+	if err != nil {
+		return nil, err
+	}
+
+	namedCache.Store(query, nq)
+	return nq, nil
+}
+
+func parseNamed(query string) (*namedQuery, error) {
+	var sb strings.Builder
+	sb.Grow(len(query))
+
+	nq := &namedQuery{}
+	positions := make(map[string]int)
+
+	n := len(query)
+	for i := 0; i < n; {
+		c := query[i]
+		var next byte
+		if i+1 < n {
+			next = query[i+1]
+		}
+
+		end, err := skipLiteral(query, i)
+		if err != nil {
+			return nil, fmt.Errorf("named query: %w", err)
+		}
+		if end > i {
+			sb.WriteString(query[i:end])
+			i = end
+			continue
+		}
+
+		switch {
+		case c == '$' && isDigit(next) && (i == 0 || !isIdentChar(query[i-1])):
+			return nil, errors.New("named query: positional parameters can't be mixed with named ones")
+
+		case c == ':' && next == ':':
+			sb.WriteString("::")
+			i += 2
+
+		case (c == ':' || c == '@' && !endsAtOperator(query, i)) && isIdentStart(next) && (i == 0 || !isIdentChar(query[i-1])):
+			end := i + 1
+			for end < n && isIdentChar(query[end]) {
+				end++
+			}
+			name := query[i+1 : end]
+
+			pos, ok := positions[name]
+			if !ok {
+				nq.names = append(nq.names, name)
+				pos = len(nq.names)
+				positions[name] = pos
+			}
+			sb.WriteByte('$')
+			sb.WriteString(strconv.Itoa(pos))
+			i = end
+
+		default:
+			sb.WriteByte(c)
+			i++
+		}
+	}
+
+	nq.sql = sb.String()
+	return nq, nil
+}
+
+// endsAtOperator reports whether the @ at query[i] ends one of the Postgres operators @@, <@ and @-@,
+// so that it isn't a placeholder. The @ of comparisons like id=@id starts a placeholder.
+func endsAtOperator(query string, i int) bool {
+	switch {
+	case i > 0 && (query[i-1] == '@' || query[i-1] == '<'):
+		return true
+	case i > 1 && query[i-1] == '-' && query[i-2] == '@':
+		return true
+	}
+	return false
+}
+
+func namedArgs(names []string, arg interface{}) ([]interface{}, error) {
+	args := make([]interface{}, len(names))
+
+	if m, ok := arg.(map[string]interface{}); ok {
+		for i, name := range names {
+			v, ok := m[name]
+			if !ok {
+				return nil, fmt.Errorf("named parameter %q not found", name)
+			}
+			args[i] = v
+		}
+		return args, nil
+	}
+
+	v := reflect.ValueOf(arg)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil, errors.New("named arg is a nil pointer")
+		}
+		v = v.Elem()
+	}
+
+	switch {
+	case v.Kind() == reflect.Struct:
+		for i, name := range names {
+			f, ok := core.FieldByTag(v, name)
+			if !ok {
+				return nil, fmt.Errorf("named parameter %q not found in %s", name, v.Type())
+			}
+			args[i] = f.Interface()
+		}
+	case v.Kind() == reflect.Map && v.Type().Key().Kind() == reflect.String:
+		for i, name := range names {
+			f := v.MapIndex(reflect.ValueOf(name).Convert(v.Type().Key()))
+			if !f.IsValid() {
+				return nil, fmt.Errorf("named parameter %q not found", name)
+			}
+			args[i] = f.Interface()
+		}
+	default:
+		return nil, fmt.Errorf("named arg must be a struct or a map with string keys but got %T", arg)
+	}
+
+	return args, nil
+}
@@ -0,0 +1,237 @@
+package easyscan
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+func Test_parseNamed(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		sql   string
+		names []string
+	}{
+		{
+			name:  "colon",
+			query: "SELECT * FROM users WHERE id = :id AND name = :name",
+			sql:   "SELECT * FROM users WHERE id = $1 AND name = $2",
+			names: []string{"id", "name"},
+		},
+		{
+			name:  "at sign",
+			query: "SELECT * FROM users WHERE id = @id",
+			sql:   "SELECT * FROM users WHERE id = $1",
+			names: []string{"id"},
+		},
+		{
+			name:  "text search operator",
+			query: "SELECT * FROM docs WHERE tsv @@to_tsquery(:q) AND tsv @@ to_tsquery(@q)",
+			sql:   "SELECT * FROM docs WHERE tsv @@to_tsquery($1) AND tsv @@ to_tsquery($1)",
+			names: []string{"q"},
+		},
+		{
+			name:  "containment operators",
+			query: "SELECT * FROM t WHERE tags @> @tags AND tags <@tags AND ids @>ids",
+			sql:   "SELECT * FROM t WHERE tags @> $1 AND tags <@tags AND ids @>ids",
+			names: []string{"tags"},
+		},
+		{
+			name:  "comparisons",
+			query: "SELECT * FROM t WHERE id=@id AND n>=@min AND a<>@b AND c!=@b",
+			sql:   "SELECT * FROM t WHERE id=$1 AND n>=$2 AND a<>$3 AND c!=$3",
+			names: []string{"id", "min", "b"},
+		},
+		{
+			name:  "length operator",
+			query: "SELECT @-@path, @-@ path FROM t WHERE id = @id",
+			sql:   "SELECT @-@path, @-@ path FROM t WHERE id = $1",
+			names: []string{"id"},
+		},
+		{
+			name:  "repeated",
+			query: "SELECT :a, :b, :a",
+			sql:   "SELECT $1, $2, $1",
+			names: []string{"a", "b"},
+		},
+		{
+			name:  "casts",
+			query: "SELECT :id::bigint, now()::date",
+			sql:   "SELECT $1::bigint, now()::date",
+			names: []string{"id"},
+		},
+		{
+			name:  "string literals",
+			query: "SELECT ':a', 'it''s :b', E'\\' :c', :d",
+			sql:   "SELECT ':a', 'it''s :b', E'\\' :c', $1",
+			names: []string{"d"},
+		},
+		{
+			name:  "quoted identifiers",
+			query: `SELECT ":a" FROM t WHERE x = :b`,
+			sql:   `SELECT ":a" FROM t WHERE x = $1`,
+			names: []string{"b"},
+		},
+		{
+			name:  "comments",
+			query: "SELECT :a -- :b\n, /* :c /* :d */ :e */ :f",
+			sql:   "SELECT $1 -- :b\n, /* :c /* :d */ :e */ $2",
+			names: []string{"a", "f"},
+		},
+		{
+			name:  "dollar quoted",
+			query: "SELECT $$ :a $$, $fn$ :b $x$ $fn$, :c",
+			sql:   "SELECT $$ :a $$, $fn$ :b $x$ $fn$, $1",
+			names: []string{"c"},
+		},
+		{
+			name:  "operators",
+			query: "SELECT data @> :filter, arr[lo:hi], a@b FROM t",
+			sql:   "SELECT data @> $1, arr[lo:hi], a@b FROM t",
+			names: []string{"filter"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nq, err := parseNamed(tt.query)
+			noError(t, err)
+			equal(t, tt.sql, nq.sql)
+			equal(t, tt.names, nq.names)
+		})
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		for query, msg := range map[string]string{
+			"SELECT 'foo":          "unterminated quoted string",
+			`SELECT "foo`:          "unterminated quoted identifier",
+			"SELECT /* foo":        "unterminated block comment",
+			"SELECT $a$ foo":       "unterminated dollar-quoted string",
+			"SELECT $1 WHERE :foo": "positional parameters",
+		} {
+			_, err := parseNamed(query)
+			errorContains(t, err, msg)
+		}
+	})
+}
+
+func TestBindNamed(t *testing.T) {
+	type audit struct {
+		CreatedBy string `db:"created_by"`
+	}
+	type filter struct {
+		ID   int     `db:"id"`
+		Name *string `db:"name"`
+		audit
+	}
+
+	const query = "SELECT * FROM users WHERE id = :id AND name = :name AND created_by = :created_by"
+	const sql = "SELECT * FROM users WHERE id = $1 AND name = $2 AND created_by = $3"
+
+	t.Run("struct", func(t *testing.T) {
+		name := "foo"
+		got, args, err := BindNamed(query, filter{ID: 1, Name: &name, audit: audit{CreatedBy: "bar"}})
+		noError(t, err)
+		equal(t, sql, got)
+		equal(t, []interface{}{1, &name, "bar"}, args)
+	})
+
+	t.Run("pointer to struct", func(t *testing.T) {
+		_, args, err := BindNamed(query, &filter{ID: 1})
+		noError(t, err)
+		equal(t, []interface{}{1, (*string)(nil), ""}, args)
+	})
+
+	t.Run("map", func(t *testing.T) {
+		got, args, err := BindNamed(query, map[string]interface{}{"id": 1, "name": "foo", "created_by": "bar"})
+		noError(t, err)
+		equal(t, sql, got)
+		equal(t, []interface{}{1, "foo", "bar"}, args)
+	})
+
+	t.Run("typed map", func(t *testing.T) {
+		_, args, err := BindNamed("SELECT :a, :b", map[string]int{"a": 1, "b": 2})
+		noError(t, err)
+		equal(t, []interface{}{1, 2}, args)
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		_, _, err := BindNamed(query, map[string]interface{}{"id": 1})
+		errorContains(t, err, `named parameter "name" not found`)
+
+		_, _, err = BindNamed("SELECT :foo", filter{})
+		errorContains(t, err, `named parameter "foo" not found`)
+	})
+
+	t.Run("unsupported arg", func(t *testing.T) {
+		_, _, err := BindNamed(query, 1)
+		errorContains(t, err, "must be a struct or a map")
+	})
+
+	t.Run("cached", func(t *testing.T) {
+		_, _, err := BindNamed(query, filter{})
+		noError(t, err)
+		_, ok := namedCache.Load(query)
+		equal(t, true, ok)
+	})
+}
+
+func TestGetNamedOptions(t *testing.T) {
+	q := &captureQuerier{rows: newFakeRows([]string{"n"}, []interface{}{nil})}
+	n := 5
+	err := GetNamed(context.Background(), q, &n, "SELECT n FROM t WHERE id = @id", map[string]interface{}{"id": 1}, WithNullZero())
+	noError(t, err)
+	equal(t, "SELECT n FROM t WHERE id = $1", q.sql)
+	equal(t, []interface{}{1}, q.args)
+	equal(t, 0, n)
+}
+
+func TestSelectNamed(t *testing.T) {
+	ctx := context.Background()
+
+	var gotQuery string
+	var gotArgs []interface{}
+	q := QuerierFunc(func(_ context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+		gotQuery, gotArgs = sql, args
+		return newFakeRows([]string{"id"}, []interface{}{1}, []interface{}{2}), nil
+	})
+
+	result := []int{42}
+	err := SelectNamed(ctx, q, &result, "SELECT id FROM users WHERE id > :id", map[string]interface{}{"id": 0}, WithMode(ModeReplace))
+	noError(t, err)
+	equal(t, "SELECT id FROM users WHERE id > $1", gotQuery)
+	equal(t, []interface{}{0}, gotArgs)
+	equal(t, []int{1, 2}, result)
+}
+
+func TestExecNamed(t *testing.T) {
+	ctx := context.Background()
+	pool, err := pgxpool.Connect(ctx, connString)
+	noError(t, err)
+	defer pool.Close()
+
+	_, err = pool.Exec(ctx, "CREATE TABLE IF NOT EXISTS easy_scan_named(id bigint, name text)")
+	noError(t, err)
+	defer func() {
+		_, e := pool.Exec(ctx, "DROP TABLE easy_scan_named")
+		if e != nil {
+			panic(e)
+		}
+	}()
+
+	type row struct {
+		ID   int64  `db:"id"`
+		Name string `db:"name"`
+	}
+
+	tag, err := ExecNamed(ctx, pool, "INSERT INTO easy_scan_named(id, name) VALUES (:id, :name)", row{ID: 1, Name: "foo"})
+	noError(t, err)
+	equal(t, int64(1), tag.RowsAffected())
+
+	var result row
+	err = GetNamed(ctx, pool, &result, "SELECT * FROM easy_scan_named WHERE id = @id", map[string]interface{}{"id": 1})
+	noError(t, err)
+	equal(t, row{ID: 1, Name: "foo"}, result)
+}
@@ -0,0 +1,50 @@
+package easyscan
+
+import (
+	"github.com/popovpsk/easyscan/internal/core"
+)
+
+// Option changes the behavior of a single Get or Select call.
+// Options are passed among the query arguments, the same way pgx accepts
+// QueryResultFormats, and are removed before the query is sent.
+type Option = core.Option
+
+// Mode defines what Select does with rows already present in the destination slice.
+type Mode = core.Mode
+
+const (
+	// ModeAppend appends scanned rows after the existing elements. This is the default.
+	ModeAppend = core.ModeAppend
+	// ModeReplace discards the existing elements before the first row is added.
+	ModeReplace = core.ModeReplace
+)
+
+// JSONCodec decodes fields tagged with json.
+type JSONCodec = core.JSONCodec
+
+// JSONDecoder reads JSON values from a stream, *json.Decoder implements it.
+type JSONDecoder = core.JSONDecoder
+
+// JSONStreamDecoder is implemented by field types that decode large json documents from a stream themselves.
+type JSONStreamDecoder = core.JSONStreamDecoder
+
+// TypeMismatchError lists the columns whose types can't be scanned into their destinations.
+type TypeMismatchError = core.TypeMismatchError
+
+// TypeMismatch is a column whose type can't be scanned into its destination.
+type TypeMismatch = core.TypeMismatch
+
+var (
+	// WithMode sets the Mode of Select.
+	WithMode = core.WithMode
+	// WithExpectedRows makes Select reserve capacity for n rows before scanning.
+	WithExpectedRows = core.WithExpectedRows
+	// WithTypeCheck makes Get and Select compare the type of every column with the type of its destination
+	// before the first row is scanned. All incompatible pairs are returned at once by a *TypeMismatchError.
+	WithTypeCheck = core.WithTypeCheck
+	// WithNullZero makes NULL scan as the zero value into every field that can't hold NULL,
+	// as if the fields were tagged with the nullzero option.
+	WithNullZero = core.WithNullZero
+	// WithJSONCodec sets the codec that decodes fields tagged with json, encoding/json is used by default.
+	WithJSONCodec = core.WithJSONCodec
+)
@@ -0,0 +1,274 @@
+package easyscan
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/popovpsk/easyscan/internal/core"
+)
+
+// PageMode defines how Page skips the rows of the previous pages.
+type PageMode int
+
+const (
+	// PageKeyset continues after the row encoded in PageRequest.After. This is the default.
+	PageKeyset PageMode = iota
+	// PageOffset skips PageRequest.Offset rows.
+	PageOffset
+)
+
+// PageRequest describes a page of Page.
+type PageRequest struct {
+	Mode PageMode
+	// After is PageInfo.Next of the previous page, empty for the first page. Used by PageKeyset.
+	After string
+	// Offset is the number of skipped rows. Used by PageOffset.
+	Offset int
+	// Limit is the maximum number of rows of the page, it must be positive.
+	Limit int
+	// OrderBy must contain tagged columns of the destination struct and identify a row uniquely,
+	// e.g. Desc("created_at"), Desc("id"). Keyset pagination requires non-null columns.
+	OrderBy []Order
+	// CountTotal makes PageOffset fill PageInfo.Total with count(*) OVER().
+	CountTotal bool
+}
+
+// PageInfo describes the loaded page.
+type PageInfo struct {
+	// Next is the cursor for PageRequest.After of the next page, empty for the last page.
+	Next string
+	// HasMore reports whether there are rows after the page.
+	HasMore bool
+	// Total is the number of rows of baseQuery, filled when PageRequest.CountTotal is set.
+	Total int64
+}
+
+var errInvalidCursor = errors.New("invalid page cursor")
+
+// cursor is the encoded position of a row in the keyset ordering.
+type cursor struct {
+	Columns []string          `json:"c"`
+	Values  []json.RawMessage `json:"v"`
+}
+
+// Page loads a page of baseQuery into dest, a pointer to a slice of tagged structs,
+// replacing its elements. baseQuery is wrapped into a subquery, the ordering and
+// the keyset predicate or offset are applied to its result.
+// The query is run by Select, so args may contain In arguments and opts apply as they do there.
+func Page(ctx context.Context, conn Querier, dest interface{}, baseQuery string, args []interface{}, req PageRequest, opts ...Option) (PageInfo, error) {
+	rowType, err := sliceElemStruct(dest)
+	if err != nil {
+		return PageInfo{}, err
+	}
+
+	if req.Limit <= 0 {
+		return PageInfo{}, errors.New("page limit must be positive")
+	}
+	if len(req.OrderBy) == 0 {
+		return PageInfo{}, errors.New("page requires OrderBy")
+	}
+
+	countTotal := req.Mode == PageOffset && req.CountTotal
+	queryArgs := append(make([]interface{}, 0, len(args)+len(req.OrderBy)+len(opts)+4), args...)
+
+	b := &strings.Builder{}
+	b.WriteString("SELECT *")
+	if countTotal {
+		b.WriteString(", count(*) OVER() AS easyscan_total")
+	}
+	b.WriteString(" FROM (")
+	b.WriteString(baseQuery)
+	b.WriteString(") AS easyscan_page")
+
+	if req.Mode == PageKeyset && req.After != "" {
+		values, err := decodeCursor(req.After, rowType, req.OrderBy)
+		if err != nil {
+			return PageInfo{}, err
+		}
+		queryArgs = writeKeysetPredicate(b, req.OrderBy, values, queryArgs)
+	}
+
+	if err = writeOrderBy(b, rowType, req.OrderBy); err != nil {
+		return PageInfo{}, err
+	}
+
+	// one extra row tells whether there is a next page
+	queryArgs = append(queryArgs, req.Limit+1)
+	b.WriteString(" LIMIT $")
+	b.WriteString(strconv.Itoa(len(queryArgs)))
+
+	if req.Mode == PageOffset && req.Offset > 0 {
+		queryArgs = append(queryArgs, req.Offset)
+		b.WriteString(" OFFSET $")
+		b.WriteString(strconv.Itoa(len(queryArgs)))
+	}
+
+	// the page always replaces the elements of dest
+	queryArgs = appendOptions(queryArgs, opts)
+	queryArgs = append(queryArgs, WithMode(ModeReplace), WithExpectedRows(req.Limit+1))
+
+	info := PageInfo{}
+	var wrap func(core.Rows) core.Rows
+	if countTotal {
+		wrap = func(rows core.Rows) core.Rows {
+			return &totalRows{Rows: rows, total: &info.Total}
+		}
+	}
+
+	err = selectRows(ctx, conn, dest, b.String(), queryArgs, wrap)
+	if err != nil {
+		return PageInfo{}, err
+	}
+
+	sv := reflect.ValueOf(dest).Elem()
+	if sv.Len() > req.Limit {
+		info.HasMore = true
+		sv.Set(sv.Slice(0, req.Limit))
+	}
+
+	if req.Mode == PageKeyset && info.HasMore {
+		last := sv.Index(req.Limit - 1)
+		if last.Kind() == reflect.Ptr {
+			last = last.Elem()
+		}
+		info.Next, err = encodeCursor(last, req.OrderBy)
+		if err != nil {
+			return PageInfo{}, err
+		}
+	}
+
+	return info, nil
+}
+
+// writeKeysetPredicate writes the rows after values in the ordering:
+// (c1 > $1) OR (c1 = $1 AND c2 < $2) ..., where > or < depends on the direction of a column.
+func writeKeysetPredicate(b *strings.Builder, orders []Order, values []interface{}, args []interface{}) []interface{} {
+	first := len(args) + 1
+	args = append(args, values...)
+
+	b.WriteString(" WHERE ")
+	for i := range orders {
+		if i > 0 {
+			b.WriteString(" OR ")
+		}
+		b.WriteByte('(')
+		for j := 0; j <= i; j++ {
+			if j > 0 {
+				b.WriteString(" AND ")
+			}
+			b.WriteString(quoteIdentifier(orders[j].Column))
+			switch {
+			case j < i:
+				b.WriteString(" = ")
+			case orders[j].Desc:
+				b.WriteString(" < ")
+			default:
+				b.WriteString(" > ")
+			}
+			b.WriteByte('$')
+			b.WriteString(strconv.Itoa(first + j))
+		}
+		b.WriteByte(')')
+	}
+	return args
+}
+
+func encodeCursor(row reflect.Value, orders []Order) (string, error) {
+	c := cursor{Columns: make([]string, len(orders)), Values: make([]json.RawMessage, len(orders))}
+	for i, o := range orders {
+		f, ok := core.FieldByTag(row, o.Column)
+		if !ok {
+			return "", fmt.Errorf("order by column %q is not allowed", o.Column)
+		}
+		v, err := json.Marshal(f.Interface())
+		if err != nil {
+			return "", fmt.Errorf("page cursor: %w", err)
+		}
+		c.Columns[i] = o.Column
+		c.Values[i] = v
+	}
+
+	data, err := json.Marshal(c)
+	if err != nil {
+		return "", fmt.Errorf("page cursor: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// decodeCursor decodes the values of the cursor into the types of the tagged fields of rowType.
+func decodeCursor(s string, rowType reflect.Type, orders []Order) ([]interface{}, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errInvalidCursor, err)
+	}
+
+	var c cursor
+	if err = json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("%w: %v", errInvalidCursor, err)
+	}
+	if len(c.Columns) != len(orders) || len(c.Values) != len(orders) {
+		return nil, fmt.Errorf("%w: it doesn't match the ordering", errInvalidCursor)
+	}
+
+	row := reflect.New(rowType).Elem()
+	values := make([]interface{}, len(orders))
+	for i, o := range orders {
+		if c.Columns[i] != o.Column {
+			return nil, fmt.Errorf("%w: it doesn't match the ordering", errInvalidCursor)
+		}
+		f, ok := core.FieldByTag(row, o.Column)
+		if !ok {
+			return nil, fmt.Errorf("order by column %q is not allowed", o.Column)
+		}
+		v := reflect.New(f.Type())
+		if err = json.Unmarshal(c.Values[i], v.Interface()); err != nil {
+			return nil, fmt.Errorf("%w: %v", errInvalidCursor, err)
+		}
+		values[i] = v.Elem().Interface()
+	}
+	return values, nil
+}
+
+// totalRows hides the trailing count(*) OVER() column and scans it into total.
+// The column types and the failed column of the underlying rows are passed through.
+type totalRows struct {
+	core.Rows
+	total *int64
+	buf   []interface{}
+}
+
+func (r *totalRows) Columns() []string {
+	columns := r.Rows.Columns()
+	return columns[:len(columns)-1]
+}
+
+func (r *totalRows) ColumnTypes() []core.ColumnType {
+	ct, ok := r.Rows.(core.ColumnTyper)
+	if !ok {
+		return nil
+	}
+	types := ct.ColumnTypes()
+	if len(types) == 0 {
+		return nil
+	}
+	return types[:len(types)-1]
+}
+
+func (r *totalRows) ScanColumnIndex(err error) (int, bool) {
+	indexer, ok := r.Rows.(core.ScanColumnIndexer)
+	if !ok {
+		return 0, false
+	}
+	return indexer.ScanColumnIndex(err)
+}
+
+func (r *totalRows) Scan(dest ...interface{}) error {
+	r.buf = append(append(r.buf[:0], dest...), r.total)
+	return r.Rows.Scan(r.buf...)
+}
@@ -0,0 +1,162 @@
+package easyscan
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type pageRow struct {
+	ID        int64     `db:"id"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+func TestPageStatement(t *testing.T) {
+	ctx := context.Background()
+	ts := time.Date(2012, 3, 4, 10, 11, 12, 0, time.UTC)
+	orderBy := []Order{Desc("created_at"), Asc("id")}
+
+	t.Run("keyset", func(t *testing.T) {
+		q := &captureQuerier{rows: newFakeRows([]string{"id", "created_at"},
+			[]interface{}{int64(1), ts}, []interface{}{int64(2), ts}, []interface{}{int64(3), ts})}
+		var result []pageRow
+		info, err := Page(ctx, q, &result, "SELECT * FROM t WHERE owner = $1", []interface{}{"foo"},
+			PageRequest{Limit: 2, OrderBy: orderBy})
+		noError(t, err)
+		equal(t, `SELECT * FROM (SELECT * FROM t WHERE owner = $1) AS easyscan_page ORDER BY "created_at" DESC, "id" LIMIT $2`, q.sql)
+		equal(t, []interface{}{"foo", 3}, q.args)
+		equal(t, 2, len(result))
+		equal(t, true, info.HasMore)
+
+		q = &captureQuerier{rows: newFakeRows([]string{"id", "created_at"}, []interface{}{int64(3), ts})}
+		var next []*pageRow
+		info, err = Page(ctx, q, &next, "SELECT * FROM t WHERE owner = $1", []interface{}{"foo"},
+			PageRequest{After: info.Next, Limit: 2, OrderBy: orderBy})
+		noError(t, err)
+		equal(t, `SELECT * FROM (SELECT * FROM t WHERE owner = $1) AS easyscan_page WHERE ("created_at" < $2) OR ("created_at" = $2 AND "id" > $3) ORDER BY "created_at" DESC, "id" LIMIT $4`, q.sql)
+		equal(t, []interface{}{"foo", ts, int64(2), 3}, q.args)
+		equal(t, 1, len(next))
+		equal(t, false, info.HasMore)
+		equal(t, "", info.Next)
+	})
+
+	t.Run("offset", func(t *testing.T) {
+		q := &captureQuerier{rows: newFakeRows([]string{"id", "created_at", "easyscan_total"},
+			[]interface{}{int64(1), ts, int64(10)})}
+		var result []pageRow
+		info, err := Page(ctx, q, &result, "SELECT * FROM t", nil,
+			PageRequest{Mode: PageOffset, Offset: 9, Limit: 5, OrderBy: orderBy, CountTotal: true})
+		noError(t, err)
+		equal(t, `SELECT *, count(*) OVER() AS easyscan_total FROM (SELECT * FROM t) AS easyscan_page ORDER BY "created_at" DESC, "id" LIMIT $1 OFFSET $2`, q.sql)
+		equal(t, []interface{}{6, 9}, q.args)
+		equal(t, PageInfo{Total: 10}, info)
+		equal(t, []pageRow{{ID: 1, CreatedAt: ts}}, result)
+	})
+
+	t.Run("options and In", func(t *testing.T) {
+		q := &captureQuerier{rows: newFakeRows([]string{"id", "created_at"}, []interface{}{int64(1), nil})}
+		var result []pageRow
+		_, err := Page(ctx, q, &result, "SELECT * FROM t WHERE id IN ($1)", []interface{}{In([]int64{1, 2})},
+			PageRequest{Limit: 2, OrderBy: orderBy}, WithNullZero())
+		noError(t, err)
+		equal(t, `SELECT * FROM (SELECT * FROM t WHERE id IN ($1, $2)) AS easyscan_page ORDER BY "created_at" DESC, "id" LIMIT $3`, q.sql)
+		equal(t, []interface{}{int64(1), int64(2), 3}, q.args)
+		equal(t, []pageRow{{ID: 1}}, result)
+	})
+
+	t.Run("count total with json", func(t *testing.T) {
+		type event struct {
+			ID   int64          `db:"id"`
+			Meta map[string]int `db:"meta,json"`
+		}
+		const oidJSONB, oidText, oidInt8 = 3802, 25, 20
+
+		rows := newFakeRows([]string{"id", "meta", "easyscan_total"}, []interface{}{int64(1), []byte(`{"a":1}`), int64(4)})
+		rows.oids = []uint32{oidInt8, oidJSONB, oidInt8}
+		var result []event
+		info, err := Page(ctx, &captureQuerier{rows: rows}, &result, "SELECT * FROM events", nil,
+			PageRequest{Mode: PageOffset, Limit: 5, OrderBy: []Order{Asc("id")}, CountTotal: true}, WithTypeCheck())
+		noError(t, err)
+		equal(t, int64(4), info.Total)
+		equal(t, []event{{ID: 1, Meta: map[string]int{"a": 1}}}, result)
+
+		rows = newFakeRows([]string{"id", "meta", "easyscan_total"}, []interface{}{int64(1), "x", int64(4)})
+		rows.oids = []uint32{oidInt8, oidText, oidInt8}
+		_, err = Page(ctx, &captureQuerier{rows: rows}, &result, "SELECT * FROM events", nil,
+			PageRequest{Mode: PageOffset, Limit: 5, OrderBy: []Order{Asc("id")}, CountTotal: true})
+		errorContains(t, err, `column "meta" of type text isn't json`)
+	})
+
+	t.Run("count total with scan error", func(t *testing.T) {
+		rows := newFakeRows([]string{"id", "created_at", "easyscan_total"}, []interface{}{"x", ts, int64(4)})
+		var result []pageRow
+		_, err := Page(ctx, &captureQuerier{rows: rows}, &result, "SELECT * FROM t", nil,
+			PageRequest{Mode: PageOffset, Limit: 5, OrderBy: orderBy, CountTotal: true})
+		var se *ScanError
+		equal(t, true, errors.As(err, &se))
+		equal(t, "id", se.Column)
+		equal(t, "pageRow.ID", se.Field)
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		var result []pageRow
+		_, err := Page(ctx, &captureQuerier{}, &result, "SELECT * FROM t", nil, PageRequest{After: "foo", Limit: 1, OrderBy: orderBy})
+		equal(t, true, errors.Is(err, errInvalidCursor))
+
+		cur, err := encodeCursor(reflect.ValueOf(pageRow{ID: 1}), []Order{Asc("id")})
+		noError(t, err)
+		_, err = Page(ctx, &captureQuerier{}, &result, "SELECT * FROM t", nil, PageRequest{After: cur, Limit: 1, OrderBy: orderBy})
+		errorContains(t, err, "doesn't match the ordering")
+	})
+
+	t.Run("invalid request", func(t *testing.T) {
+		var result []pageRow
+		_, err := Page(ctx, &captureQuerier{}, &result, "SELECT * FROM t", nil, PageRequest{OrderBy: orderBy})
+		errorContains(t, err, "limit must be positive")
+
+		_, err = Page(ctx, &captureQuerier{}, &result, "SELECT * FROM t", nil, PageRequest{Limit: 1})
+		errorContains(t, err, "requires OrderBy")
+
+		_, err = Page(ctx, &captureQuerier{}, &result, "SELECT * FROM t", nil, PageRequest{Limit: 1, OrderBy: []Order{Asc("name")}})
+		errorContains(t, err, "is not allowed")
+	})
+}
+
+func TestPage(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	pool, err := pgxpool.Connect(ctx, connString)
+	noError(t, err)
+	defer pool.Close()
+
+	const query = `SELECT id, '2012-03-04'::timestamp + (id % 3) * interval '1 day' AS created_at FROM generate_series(1, $1::int) AS id`
+	orderBy := []Order{Desc("created_at"), Asc("id")}
+
+	var ids []int64
+	var result []pageRow
+	req := PageRequest{Limit: 4, OrderBy: orderBy}
+	for {
+		info, err := Page(ctx, pool, &result, query, []interface{}{10}, req)
+		noError(t, err)
+		for _, r := range result {
+			ids = append(ids, r.ID)
+		}
+		if !info.HasMore {
+			break
+		}
+		req.After = info.Next
+	}
+	equal(t, []int64{2, 5, 8, 1, 4, 7, 10, 3, 6, 9}, ids)
+
+	info, err := Page(ctx, pool, &result, query, []interface{}{10},
+		PageRequest{Mode: PageOffset, Offset: 8, Limit: 4, OrderBy: orderBy, CountTotal: true})
+	noError(t, err)
+	equal(t, int64(10), info.Total)
+	equal(t, false, info.HasMore)
+	equal(t, 2, len(result))
+	equal(t, int64(6), result[0].ID)
+}
@@ -0,0 +1,159 @@
+// Package pgxv5 provides Get and Select for github.com/jackc/pgx/v5.
+// It shares the struct mapping with the root package, so both drivers scan the same way.
+//
+// The features built on pgx v4 types stay in the root package: In arguments, WithEmptyIn and WithRetry
+// are rejected with an error, the transactions of easyscan.InTx aren't picked up from the context,
+// and errors of pgx v5 aren't wrapped into easyscan.ConstraintError.
+package pgxv5
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/popovpsk/easyscan/internal/core"
+)
+
+var ErrMoreThanOneRow = core.ErrMoreThanOneRow
+
+// JSONCodec decodes fields tagged with json. It is the same type as easyscan.JSONCodec.
+type JSONCodec = core.JSONCodec
+
+// ScanError is a failure to scan a column. It is the same type as easyscan.ScanError.
+type ScanError = core.ScanError
+
+// Querier is the source of rows for Get and Select.
+// *pgx.Conn, *pgxpool.Pool, *pgxpool.Conn and pgx.Tx implement it.
+type Querier interface {
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+}
+
+var (
+	_ Querier = (*pgx.Conn)(nil)
+	_ Querier = (*pgxpool.Pool)(nil)
+	_ Querier = (*pgxpool.Conn)(nil)
+	_ Querier = (pgx.Tx)(nil)
+)
+
+// Option changes the behavior of a single Get or Select call.
+// It is the same type as easyscan.Option.
+type Option = core.Option
+
+// Mode defines what Select does with rows already present in the destination slice.
+type Mode = core.Mode
+
+const (
+	// ModeAppend appends scanned rows after the existing elements. This is the default.
+	ModeAppend = core.ModeAppend
+	// ModeReplace discards the existing elements before the first row is added.
+	ModeReplace = core.ModeReplace
+)
+
+// The options are shared with the root package, an option of either package works with both.
+var (
+	// WithMode sets the Mode of Select.
+	WithMode = core.WithMode
+	// WithExpectedRows makes Select reserve capacity for n rows before scanning.
+	WithExpectedRows = core.WithExpectedRows
+	// WithTypeCheck makes Get and Select compare the type of every column with the type of its destination
+	// before the first row is scanned.
+	WithTypeCheck = core.WithTypeCheck
+	// WithNullZero makes NULL scan as the zero value into every field that can't hold NULL.
+	WithNullZero = core.WithNullZero
+	// WithJSONCodec sets the codec that decodes fields tagged with json, encoding/json is used by default.
+	WithJSONCodec = core.WithJSONCodec
+)
+
+// rowsAdapter adds column names to pgx.Rows.
+type rowsAdapter struct {
+	pgx.Rows
+}
+
+func (r rowsAdapter) Columns() []string {
+	fds := r.FieldDescriptions()
+	columns := make([]string, len(fds))
+	for i := range fds {
+		columns[i] = fds[i].Name
+	}
+	return columns
+}
+
+// ScanColumnIndex returns the failed column of a Scan error.
+func (r rowsAdapter) ScanColumnIndex(err error) (int, bool) {
+	var argErr pgx.ScanArgError
+	if errors.As(err, &argErr) {
+		return argErr.ColumnIndex, true
+	}
+	return 0, false
+}
+
+// typeNames resolves the names of the built-in types.
+var typeNames = pgtype.NewMap()
+
+func (r rowsAdapter) ColumnTypes() []core.ColumnType {
+	fds := r.FieldDescriptions()
+	types := make([]core.ColumnType, len(fds))
+	for i := range fds {
+		types[i].OID = fds[i].DataTypeOID
+		if t, ok := typeNames.TypeForOID(fds[i].DataTypeOID); ok {
+			types[i].Name = t.Name
+		}
+	}
+	return types
+}
+
+func Get(ctx context.Context, conn Querier, dest interface{}, query string, args ...interface{}) error {
+	if conn == nil {
+		return errors.New("conn is nil")
+	}
+
+	row, err := core.NewRow(dest)
+	if err != nil {
+		return err
+	}
+
+	opts, args := core.ExtractOptions(args)
+	if err = core.CheckRootOnly("pgxv5", opts, args); err != nil {
+		return err
+	}
+
+	rows, err := conn.Query(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("query: %w", err)
+	}
+	defer rows.Close()
+
+	err = row.Scan(rowsAdapter{rows}, opts)
+	if err == core.ErrNoRows {
+		return pgx.ErrNoRows
+	}
+	return err
+}
+
+func Select(ctx context.Context, conn Querier, dest interface{}, query string, args ...interface{}) error {
+	if conn == nil {
+		return errors.New("conn is nil")
+	}
+
+	slice, err := core.NewSlice(dest)
+	if err != nil {
+		return err
+	}
+
+	opts, args := core.ExtractOptions(args)
+	if err = core.CheckRootOnly("pgxv5", opts, args); err != nil {
+		return err
+	}
+
+	rows, err := conn.Query(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("query: %w", err)
+	}
+	defer rows.Close()
+
+	return slice.Scan(rowsAdapter{rows}, opts)
+}
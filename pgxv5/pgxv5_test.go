@@ -0,0 +1,181 @@
+package pgxv5
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/popovpsk/easyscan"
+)
+
+const connString = "user=postgres password=postgres host=localhost dbname=easyscan port=5432"
+
+type RowChanges struct {
+	CreatedAt time.Time  `db:"created_at"`
+	UpdatedAt *time.Time `db:"updated_at"`
+}
+
+type person struct {
+	ID   int     `db:"id"`
+	Name *string `db:"name"`
+	RowChanges
+}
+
+func TestScanColumnIndex(t *testing.T) {
+	i, ok := rowsAdapter{}.ScanColumnIndex(fmt.Errorf("scan: %w", pgx.ScanArgError{ColumnIndex: 2, Err: errors.New("bad")}))
+	if !ok || i != 2 {
+		t.Fatalf("expected column 2 but got %d, %v", i, ok)
+	}
+	_, ok = rowsAdapter{}.ScanColumnIndex(errors.New("bad"))
+	if ok {
+		t.Fatal("expected no column")
+	}
+}
+
+// unusedQuerier fails the test when a query is sent.
+type unusedQuerier struct {
+	t *testing.T
+}
+
+func (q unusedQuerier) Query(context.Context, string, ...interface{}) (pgx.Rows, error) {
+	q.t.Fatal("the query must not be sent")
+	return nil, nil
+}
+
+func TestRootOnlyOptions(t *testing.T) {
+	ctx := context.Background()
+	q := unusedQuerier{t}
+
+	var ids []int
+	err := Select(ctx, q, &ids, "SELECT id FROM users WHERE id IN ($1)", easyscan.In([]int{1, 2}))
+	if err == nil || !strings.Contains(err.Error(), "pgxv5: argument $1: In isn't supported") {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	var id int
+	err = Get(ctx, q, &id, "SELECT 1", easyscan.WithRetry(easyscan.DefaultRetryPolicy))
+	if err == nil || !strings.Contains(err.Error(), "pgxv5: WithRetry isn't supported") {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
+
+func TestGet(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, connString)
+	noError(t, err)
+	defer pool.Close()
+
+	t.Run("primitive", func(t *testing.T) {
+		var result int
+		err = Get(ctx, pool, &result, "SELECT 1")
+		noError(t, err)
+		equal(t, 1, result)
+	})
+
+	t.Run("embedded struct", func(t *testing.T) {
+		var result person
+		err = Get(ctx, pool, &result, `SELECT 10 as id, NULL::text as name, '2012-03-04 10:11:12'::timestamp as created_at, 1 as extra`)
+		noError(t, err)
+		equal(t, 10, result.ID)
+		equal(t, (*string)(nil), result.Name)
+		equal(t, time.Date(2012, 3, 4, 10, 11, 12, 0, time.UTC), result.CreatedAt.UTC())
+	})
+
+	t.Run("no rows", func(t *testing.T) {
+		var result int
+		err = Get(ctx, pool, &result, "SELECT 1 WHERE 1=0")
+		equal(t, pgx.ErrNoRows, err)
+	})
+
+	t.Run("more than 1 rows", func(t *testing.T) {
+		var result int
+		err = Get(ctx, pool, &result, "SELECT generate_series(0, 9)")
+		equal(t, ErrMoreThanOneRow, err)
+	})
+
+	t.Run("nil conn", func(t *testing.T) {
+		var result int
+		err = Get(ctx, nil, &result, "SELECT 1")
+		errorContains(t, err, "conn is nil")
+	})
+}
+
+func TestSelect(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, connString)
+	noError(t, err)
+	defer pool.Close()
+
+	t.Run("primitive", func(t *testing.T) {
+		var result []int
+		err = Select(ctx, pool, &result, "SELECT generate_series(0, 9)")
+		noError(t, err)
+		equal(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, result)
+	})
+
+	t.Run("pointers to structs", func(t *testing.T) {
+		result := []*person{{ID: 42}}
+		err = Select(ctx, pool, &result, `SELECT generate_series(1, 3) as id, 'foo' as name`, WithMode(ModeReplace))
+		noError(t, err)
+		equal(t, 3, len(result))
+		for i, p := range result {
+			equal(t, i+1, p.ID)
+			equal(t, "foo", *p.Name)
+		}
+	})
+
+	t.Run("no matches", func(t *testing.T) {
+		var result []person
+		err = Select(ctx, pool, &result, "SELECT 1 as foo")
+		errorContains(t, err, "have no matches to columns")
+	})
+
+	t.Run("not a slice", func(t *testing.T) {
+		var result int
+		err = Select(ctx, pool, &result, "SELECT 1")
+		errorContains(t, err, "expected a slice but got")
+	})
+}
+
+func equal(t *testing.T, l, r interface{}) {
+	if !reflect.DeepEqual(l, r) {
+		fmt.Printf("%v not equal %v\n", js(l), js(r))
+		t.Fail()
+	}
+}
+
+func js(v interface{}) string {
+	result, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	return string(result)
+}
+
+func noError(t *testing.T, err error) {
+	if err != nil {
+		t.Fail()
+		panic(err)
+	}
+}
+
+func errorContains(t *testing.T, err error, str string) {
+	if err == nil {
+		t.Fail()
+		panic("error is nil")
+	}
+	if !strings.Contains(err.Error(), str) {
+		t.Fail()
+		panic(fmt.Sprintf("error %v does not contains %s", err, str))
+	}
+}
@@ -0,0 +1,90 @@
+package easyscan
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// Querier is the source of rows for Get and Select.
+// *pgx.Conn, *pgxpool.Pool, *pgxpool.Conn and pgx.Tx implement it.
+type Querier interface {
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+}
+
+var (
+	_ Querier = (*pgx.Conn)(nil)
+	_ Querier = (*pgxpool.Pool)(nil)
+	_ Querier = (*pgxpool.Conn)(nil)
+	_ Querier = (pgx.Tx)(nil)
+)
+
+// Execer runs statements that return no rows.
+// *pgx.Conn, *pgxpool.Pool, *pgxpool.Conn and pgx.Tx implement it.
+type Execer interface {
+	Exec(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error)
+}
+
+var (
+	_ Execer = (*pgx.Conn)(nil)
+	_ Execer = (*pgxpool.Pool)(nil)
+	_ Execer = (*pgxpool.Conn)(nil)
+	_ Execer = (pgx.Tx)(nil)
+)
+
+// Copier runs the COPY protocol.
+// *pgx.Conn, *pgxpool.Pool, *pgxpool.Conn and pgx.Tx implement it.
+type Copier interface {
+	CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error)
+}
+
+var (
+	_ Copier = (*pgx.Conn)(nil)
+	_ Copier = (*pgxpool.Pool)(nil)
+	_ Copier = (*pgxpool.Conn)(nil)
+	_ Copier = (pgx.Tx)(nil)
+)
+
+var errRowsConsumed = errors.New("rows are already consumed")
+
+// QuerierFunc is an adapter to use an ordinary function as a Querier.
+type QuerierFunc func(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+
+// Query calls f(ctx, sql, args...).
+func (f QuerierFunc) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	return f(ctx, sql, args...)
+}
+
+// resultsQuerier is a Querier that returns results it already has instead of running the query.
+// Running the query again isn't possible, so WithRetry is ignored for it.
+type resultsQuerier func(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+
+func (f resultsQuerier) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	return f(ctx, sql, args...)
+}
+
+// FromBatchResults returns a Querier that reads the results of the queued queries one by one.
+// The sql and args passed to Query are ignored, the batch already contains them.
+// WithRetry has no effect with it.
+func FromBatchResults(br pgx.BatchResults) Querier {
+	return resultsQuerier(func(context.Context, string, ...interface{}) (pgx.Rows, error) {
+		return br.Query()
+	})
+}
+
+// FromRows returns a Querier that returns rows on the first Query call.
+// It lets Get and Select scan rows that were obtained elsewhere.
+// WithRetry has no effect with it.
+func FromRows(rows pgx.Rows) Querier {
+	return resultsQuerier(func(context.Context, string, ...interface{}) (pgx.Rows, error) {
+		if rows == nil {
+			return nil, errRowsConsumed
+		}
+		r := rows
+		rows = nil
+		return r, nil
+	})
+}
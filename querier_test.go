@@ -0,0 +1,196 @@
+package easyscan
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgproto3/v2"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// fakeRows is an in-memory pgx.Rows used to test the scanning code without a database.
+type fakeRows struct {
+	columns []string
+	// oids are the column type OIDs, 0 when empty
+	oids   []uint32
+	values [][]interface{}
+	idx    int
+	err    error
+	closed bool
+}
+
+func newFakeRows(columns []string, values ...[]interface{}) *fakeRows {
+	return &fakeRows{columns: columns, values: values, idx: -1}
+}
+
+func (r *fakeRows) Close() {
+	r.closed = true
+}
+
+func (r *fakeRows) Err() error {
+	return r.err
+}
+
+func (r *fakeRows) CommandTag() pgconn.CommandTag {
+	return pgconn.CommandTag(fmt.Sprintf("SELECT %d", len(r.values)))
+}
+
+func (r *fakeRows) FieldDescriptions() []pgproto3.FieldDescription {
+	result := make([]pgproto3.FieldDescription, len(r.columns))
+	for i, c := range r.columns {
+		result[i].Name = []byte(c)
+		if i < len(r.oids) {
+			result[i].DataTypeOID = r.oids[i]
+		}
+	}
+	return result
+}
+
+func (r *fakeRows) Next() bool {
+	if r.closed {
+		return false
+	}
+	r.idx++
+	if r.idx >= len(r.values) {
+		r.Close()
+		return false
+	}
+	return true
+}
+
+func (r *fakeRows) Scan(dest ...interface{}) error {
+	row := r.values[r.idx]
+	if len(dest) != len(row) {
+		return fmt.Errorf("number of field descriptions must equal number of destinations, got %d and %d", len(row), len(dest))
+	}
+	for i, d := range dest {
+		if d == nil {
+			continue
+		}
+		if s, ok := d.(sql.Scanner); ok {
+			if err := s.Scan(row[i]); err != nil {
+				return r.fatal(pgx.ScanArgError{ColumnIndex: i, Err: err})
+			}
+			continue
+		}
+		dv := reflect.ValueOf(d).Elem()
+		if row[i] == nil {
+			dv.Set(reflect.Zero(dv.Type()))
+			continue
+		}
+		v := reflect.ValueOf(row[i])
+		if dv.Kind() == reflect.Ptr && v.Type().ConvertibleTo(dv.Type().Elem()) {
+			dv.Set(reflect.New(dv.Type().Elem()))
+			dv = dv.Elem()
+		}
+		if !v.Type().ConvertibleTo(dv.Type()) {
+			return r.fatal(pgx.ScanArgError{ColumnIndex: i, Err: fmt.Errorf("cannot assign %v into %s", row[i], dv.Type())})
+		}
+		dv.Set(v.Convert(dv.Type()))
+	}
+	return nil
+}
+
+// fatal closes the rows like pgx does on a failed Scan, the row can't be scanned again.
+func (r *fakeRows) fatal(err error) error {
+	r.err = err
+	r.Close()
+	r.values = nil
+	return err
+}
+
+func (r *fakeRows) Values() ([]interface{}, error) {
+	return r.values[r.idx], nil
+}
+
+func (r *fakeRows) RawValues() [][]byte {
+	return nil
+}
+
+func fakeQuerier(rows *fakeRows) Querier {
+	return QuerierFunc(func(context.Context, string, ...interface{}) (pgx.Rows, error) {
+		return rows, nil
+	})
+}
+
+func TestQuerierFunc(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	type user struct {
+		ID   int    `db:"id"`
+		Name string `db:"name"`
+	}
+
+	t.Run("select", func(t *testing.T) {
+		var gotQuery string
+		var gotArgs []interface{}
+		q := QuerierFunc(func(_ context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+			gotQuery, gotArgs = sql, args
+			return newFakeRows([]string{"id", "name"}, []interface{}{1, "foo"}, []interface{}{2, "bar"}), nil
+		})
+
+		var result []user
+		err := Select(ctx, q, &result, "SELECT id, name FROM users WHERE id > $1", WithExpectedRows(2), 0)
+		noError(t, err)
+		equal(t, "SELECT id, name FROM users WHERE id > $1", gotQuery)
+		equal(t, []interface{}{0}, gotArgs)
+		equal(t, []user{{ID: 1, Name: "foo"}, {ID: 2, Name: "bar"}}, result)
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		queryErr := errors.New("connection reset")
+		q := QuerierFunc(func(context.Context, string, ...interface{}) (pgx.Rows, error) {
+			return nil, queryErr
+		})
+
+		var result user
+		err := Get(ctx, q, &result, "SELECT 1")
+		equal(t, true, errors.Is(err, queryErr))
+	})
+
+	t.Run("from rows", func(t *testing.T) {
+		q := FromRows(newFakeRows([]string{"id"}, []interface{}{7}))
+
+		var result int
+		err := Get(ctx, q, &result, "")
+		noError(t, err)
+		equal(t, 7, result)
+
+		err = Get(ctx, q, &result, "")
+		equal(t, true, errors.Is(err, errRowsConsumed))
+	})
+}
+
+func TestFromBatchResults(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	pool, err := pgxpool.Connect(ctx, connString)
+	noError(t, err)
+	defer pool.Close()
+
+	b := &pgx.Batch{}
+	b.Queue("SELECT generate_series(0, 2)")
+	b.Queue("SELECT 'foo'")
+
+	br := pool.SendBatch(ctx, b)
+	defer br.Close()
+
+	q := FromBatchResults(br)
+
+	var ints []int
+	err = Select(ctx, q, &ints, "")
+	noError(t, err)
+	equal(t, []int{0, 1, 2}, ints)
+
+	var str string
+	err = Get(ctx, q, &str, "")
+	noError(t, err)
+	equal(t, "foo", str)
+}
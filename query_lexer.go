@@ -0,0 +1,127 @@
+package easyscan
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// skipLiteral returns the index after the string literal, quoted identifier, comment
+// or dollar-quoted string starting at i, or i when none of them starts there.
+// Placeholders inside of them must not be rewritten.
+func skipLiteral(query string, i int) (int, error) {
+	c := query[i]
+	var next byte
+	if i+1 < len(query) {
+		next = query[i+1]
+	}
+
+	switch {
+	case c == '\'':
+		escapes := i > 0 && (query[i-1] == 'E' || query[i-1] == 'e') && (i == 1 || !isIdentChar(query[i-2]))
+		end := quotedEnd(query, i, escapes)
+		if end == -1 {
+			return 0, errors.New("unterminated quoted string")
+		}
+		return end, nil
+
+	case c == '"':
+		end := strings.IndexByte(query[i+1:], '"')
+		if end == -1 {
+			return 0, errors.New("unterminated quoted identifier")
+		}
+		return end + i + 2, nil
+
+	case c == '-' && next == '-':
+		end := strings.IndexByte(query[i:], '\n')
+		if end == -1 {
+			return len(query), nil
+		}
+		return end + i, nil
+
+	case c == '/' && next == '*':
+		end := blockCommentEnd(query, i)
+		if end == -1 {
+			return 0, errors.New("unterminated block comment")
+		}
+		return end, nil
+
+	case c == '$' && !isDigit(next) && (i == 0 || !isIdentChar(query[i-1])):
+		return dollarQuotedEnd(query, i)
+	}
+
+	return i, nil
+}
+
+// quotedEnd returns the index after the closing quote of the literal starting at start.
+func quotedEnd(query string, start int, escapes bool) int {
+	for i := start + 1; i < len(query); i++ {
+		switch query[i] {
+		case '\\':
+			if escapes {
+				i++
+			}
+		case '\'':
+			if i+1 < len(query) && query[i+1] == '\'' {
+				i++
+				continue
+			}
+			return i + 1
+		}
+	}
+	return -1
+}
+
+// blockCommentEnd returns the index after the comment starting at start, block comments nest in Postgres.
+func blockCommentEnd(query string, start int) int {
+	depth := 0
+	for i := start; i+1 < len(query); i++ {
+		switch {
+		case query[i] == '/' && query[i+1] == '*':
+			depth++
+			i++
+		case query[i] == '*' && query[i+1] == '/':
+			depth--
+			i++
+			if depth == 0 {
+				return i + 1
+			}
+		}
+	}
+	return -1
+}
+
+// dollarQuotedEnd returns the index after the dollar-quoted string starting at start,
+// or start when the $ doesn't open one.
+func dollarQuotedEnd(query string, start int) (int, error) {
+	i := start + 1
+	for i < len(query) && isIdentChar(query[i]) && query[i] != '$' {
+		i++
+	}
+	if i >= len(query) || query[i] != '$' {
+		return start, nil
+	}
+
+	tag := query[start : i+1]
+	end := strings.Index(query[i+1:], tag)
+	if end == -1 {
+		return 0, fmt.Errorf("unterminated dollar-quoted string %s", tag)
+	}
+	return i + 1 + end + len(tag), nil
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c >= 0x80
+}
+
+func isIdentChar(c byte) bool {
+	return isIdentStart(c) || isDigit(c) || c == '$'
+}
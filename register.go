@@ -0,0 +1,75 @@
+package easyscan
+
+import (
+	"reflect"
+
+	"github.com/popovpsk/easyscan/internal/core"
+)
+
+// MappingError lists the problems found by Register and Validate.
+type MappingError = core.MappingError
+
+// MappingProblem is a single problem of a destination type.
+type MappingProblem = core.Problem
+
+// tagOptions are the db tag options known to the package.
+var tagOptions = map[string]bool{
+	tagGenerated:     true,
+	tagReadonly:      true,
+	core.TagNullZero: true,
+	core.TagDefault:  true,
+	core.TagJSON:     true,
+}
+
+func init() {
+	for o := range exampleOperators {
+		tagOptions[o] = true
+	}
+}
+
+// Register validates the types of values, structs or pointers to them, and caches their mapping,
+// so that mapping problems are found at startup rather than by the first query.
+// Every problem of every type is reported at once by a *MappingError, types with problems aren't cached.
+func Register(values ...interface{}) error {
+	return validate(values, true)
+}
+
+// MustRegister is Register that panics on an error, it is meant for init functions and package variables.
+func MustRegister(values ...interface{}) {
+	if err := Register(values...); err != nil {
+		panic(err)
+	}
+}
+
+// Validate runs the checks of Register without caching the types, e.g. in a unit test.
+func Validate(values ...interface{}) error {
+	return validate(values, false)
+}
+
+func validate(values []interface{}, register bool) error {
+	var problems []core.Problem
+	for _, v := range values {
+		t := reflect.TypeOf(v)
+		for t != nil && t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if t == nil {
+			problems = append(problems, core.Problem{Type: reflect.TypeOf((*interface{})(nil)).Elem(), Message: "nil value"})
+			continue
+		}
+
+		typeProblems := core.ValidateType(t, tagOptions)
+		if len(typeProblems) > 0 {
+			problems = append(problems, typeProblems...)
+			continue
+		}
+		if register {
+			core.Fields(t)
+		}
+	}
+
+	if len(problems) > 0 {
+		return &MappingError{Problems: problems}
+	}
+	return nil
+}
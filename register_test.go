@@ -0,0 +1,58 @@
+package easyscan
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRegister(t *testing.T) {
+	type valid struct {
+		ID   int64  `db:"id,generated"`
+		Name string `db:"name,ilike"`
+	}
+	type duplicate struct {
+		ID    int64 `db:"id"`
+		Other int64 `db:"id"`
+	}
+	type unexported struct {
+		id int64 `db:"id"`
+	}
+	type defaults struct {
+		Currency string `db:"currency,default=USD"`
+		Retries  int    `db:"retries,default=three"`
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		noError(t, Validate(valid{}, &valid{}))
+		noError(t, Register(valid{}))
+		MustRegister(&valid{})
+	})
+
+	t.Run("all problems", func(t *testing.T) {
+		err := Register(duplicate{}, valid{}, &unexported{}, 1)
+		var me *MappingError
+		equal(t, true, errors.As(err, &me))
+		equal(t, 3, len(me.Problems))
+		equal(t, reflect.TypeOf(duplicate{}), me.Problems[0].Type)
+		equal(t, "duplicate.Other", me.Problems[0].Field)
+		equal(t, reflect.TypeOf(unexported{}), me.Problems[1].Type)
+		equal(t, true, strings.Contains(err.Error(), "expected a struct but got int"))
+	})
+
+	t.Run("invalid default", func(t *testing.T) {
+		err := Validate(defaults{})
+		var me *MappingError
+		equal(t, true, errors.As(err, &me))
+		equal(t, 1, len(me.Problems))
+		equal(t, "defaults.Retries", me.Problems[0].Field)
+	})
+
+	t.Run("must register", func(t *testing.T) {
+		defer func() {
+			equal(t, true, recover() != nil)
+		}()
+		MustRegister(duplicate{})
+	})
+}
@@ -0,0 +1,131 @@
+package easyscan
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"reflect"
+	"time"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+
+	"github.com/popovpsk/easyscan/internal/core"
+)
+
+// RetryPolicy defines how a call failed with a serialization failure or a deadlock is repeated.
+// Zero fields are taken from DefaultRetryPolicy.
+type RetryPolicy = core.RetryPolicy
+
+// DefaultRetryPolicy is used for the zero fields of a RetryPolicy.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	MinBackoff:  10 * time.Millisecond,
+	MaxBackoff:  time.Second,
+}
+
+const (
+	sqlStateSerializationFailure = "40001"
+	sqlStateDeadlockDetected     = "40P01"
+)
+
+// WithRetry makes Get and Select run the query again when it fails with an error IsRetryable accepts.
+// The destination is restored to its value before the call between the runs.
+// The option is ignored when the query runs in a transaction, a failed statement aborts the whole transaction,
+// and for the queriers of FromRows, FromBatchResults and the queries of a Batch, which can't be run again.
+func WithRetry(p RetryPolicy) Option {
+	return core.OptionFunc(func(o *core.Options) {
+		o.Retry = &p
+	})
+}
+
+// IsRetryable reports whether err is a serialization failure or a deadlock.
+func IsRetryable(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+	return pgErr.Code == sqlStateSerializationFailure || pgErr.Code == sqlStateDeadlockDetected
+}
+
+// InTxRetry is InTx that runs the whole transaction again when it fails with an error IsRetryable accepts.
+// A call nested in a transaction of db only creates a savepoint and isn't repeated.
+func InTxRetry(ctx context.Context, db TxBeginner, opts pgx.TxOptions, p RetryPolicy, fn func(ctx context.Context) error) error {
+	if parent, ok := ctx.Value(txKey{}).(*txState); ok && parent.owns(db) {
+		return InTx(ctx, db, opts, fn)
+	}
+	return retry(ctx, &p, nil, func() error {
+		return InTx(ctx, db, opts, fn)
+	})
+}
+
+// retryPolicy returns the policy of opts unless conn is a transaction or returns results it already has.
+func retryPolicy(conn Querier, opts core.Options) *RetryPolicy {
+	switch conn.(type) {
+	case pgx.Tx, resultsQuerier:
+		return nil
+	}
+	return opts.Retry
+}
+
+// retry runs fn until it succeeds, fails with an error that isn't retryable or the attempts are exhausted.
+// dest, if not nil, is restored between the runs.
+func retry(ctx context.Context, p *RetryPolicy, dest interface{}, fn func() error) error {
+	if p == nil {
+		return fn()
+	}
+
+	var target, saved reflect.Value
+	if dest != nil {
+		target = reflect.ValueOf(dest).Elem()
+		saved = reflect.New(target.Type()).Elem()
+		saved.Set(target)
+	}
+
+	maxAttempts := p.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = DefaultRetryPolicy.MaxAttempts
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= maxAttempts || !IsRetryable(err) {
+			return err
+		}
+
+		if target.IsValid() {
+			target.Set(saved)
+		}
+
+		timer := time.NewTimer(backoff(p, attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// backoff returns the delay after the failed run number attempt,
+// a random duration between the half and the whole of the exponential delay.
+func backoff(p *RetryPolicy, attempt int) time.Duration {
+	minBackoff, maxBackoff := p.MinBackoff, p.MaxBackoff
+	if minBackoff <= 0 {
+		minBackoff = DefaultRetryPolicy.MinBackoff
+	}
+	if maxBackoff <= 0 {
+		maxBackoff = DefaultRetryPolicy.MaxBackoff
+	}
+
+	d := minBackoff
+	for i := 1; i < attempt && d < maxBackoff; i++ {
+		d *= 2
+	}
+	if d > maxBackoff {
+		d = maxBackoff
+	}
+
+	half := d / 2
+	return half + time.Duration(rand.Int63n(int64(d-half)+1))
+}
@@ -0,0 +1,177 @@
+package easyscan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+func TestRetry(t *testing.T) {
+	ctx := context.Background()
+	policy := RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond}
+	serialization := &pgconn.PgError{Code: "40001"}
+
+	// failingQuerier fails the first n queries with err, then returns rows
+	failingQuerier := func(n int, err error, rows func() *fakeRows) (Querier, *int) {
+		calls := 0
+		return QuerierFunc(func(context.Context, string, ...interface{}) (pgx.Rows, error) {
+			calls++
+			if calls <= n {
+				return nil, err
+			}
+			return rows(), nil
+		}), &calls
+	}
+
+	t.Run("is retryable", func(t *testing.T) {
+		equal(t, true, IsRetryable(fmt.Errorf("query: %w", serialization)))
+		equal(t, true, IsRetryable(&pgconn.PgError{Code: "40P01"}))
+		equal(t, false, IsRetryable(&pgconn.PgError{Code: "23505"}))
+		equal(t, false, IsRetryable(errors.New("40001")))
+	})
+
+	t.Run("select", func(t *testing.T) {
+		calls := 0
+		q := QuerierFunc(func(context.Context, string, ...interface{}) (pgx.Rows, error) {
+			calls++
+			rows := newFakeRows([]string{"n"}, []interface{}{1}, []interface{}{2})
+			if calls == 1 {
+				// the first run fails after a row is added
+				rows.values = rows.values[:1]
+				rows.err = serialization
+			}
+			return rows, nil
+		})
+
+		result := []int{0}
+		err := Select(ctx, q, &result, "SELECT n", WithRetry(policy))
+		noError(t, err)
+		equal(t, 2, calls)
+		equal(t, []int{0, 1, 2}, result)
+	})
+
+	t.Run("get", func(t *testing.T) {
+		q, calls := failingQuerier(2, serialization, func() *fakeRows {
+			return newFakeRows([]string{"n"}, []interface{}{7})
+		})
+		var result int
+		err := Get(ctx, q, &result, "SELECT 7", WithRetry(policy))
+		noError(t, err)
+		equal(t, 3, *calls)
+		equal(t, 7, result)
+	})
+
+	t.Run("attempts exhausted", func(t *testing.T) {
+		q, calls := failingQuerier(5, serialization, nil)
+		var result int
+		err := Get(ctx, q, &result, "SELECT 7", WithRetry(policy))
+		equal(t, true, errors.Is(err, serialization))
+		equal(t, 3, *calls)
+	})
+
+	t.Run("not retryable", func(t *testing.T) {
+		q, calls := failingQuerier(1, &pgconn.PgError{Code: "23505"}, nil)
+		var result int
+		err := Get(ctx, q, &result, "SELECT 7", WithRetry(policy))
+		notNilError(t, err)
+		equal(t, 1, *calls)
+	})
+
+	t.Run("no policy", func(t *testing.T) {
+		q, calls := failingQuerier(1, serialization, nil)
+		var result int
+		err := Get(ctx, q, &result, "SELECT 7")
+		notNilError(t, err)
+		equal(t, 1, *calls)
+	})
+
+	t.Run("rows", func(t *testing.T) {
+		rows := newFakeRows([]string{"n"})
+		rows.err = serialization
+		var result int
+		err := Get(ctx, FromRows(rows), &result, "", WithRetry(policy))
+		equal(t, true, errors.Is(err, serialization))
+	})
+
+	t.Run("batch", func(t *testing.T) {
+		failed := newFakeRows([]string{"n"})
+		failed.err = serialization
+		fb := &fakeBatch{rows: []*fakeRows{
+			failed,
+			newFakeRows([]string{"n"}, []interface{}{2}),
+		}}
+
+		var first, second []int
+		b := NewBatch()
+		b.Select(&first, "SELECT 1", WithRetry(policy))
+		b.Select(&second, "SELECT 2")
+		err := b.Send(ctx, fb)
+		equal(t, true, errors.Is(err, serialization))
+		// the result of the second query isn't read by a retry of the first one
+		equal(t, 1, len(fb.rows))
+		equal(t, 0, len(first))
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		q, calls := failingQuerier(5, serialization, nil)
+		canceled, cancel := context.WithCancel(ctx)
+		cancel()
+		var result int
+		err := Get(canceled, q, &result, "SELECT 7", WithRetry(RetryPolicy{MaxAttempts: 5, MinBackoff: time.Hour}))
+		equal(t, true, errors.Is(err, serialization))
+		equal(t, 1, *calls)
+	})
+
+	t.Run("transaction", func(t *testing.T) {
+		db := &fakeDB{}
+		runs := 0
+		err := InTxRetry(ctx, db, pgx.TxOptions{IsoLevel: pgx.Serializable}, policy, func(ctx context.Context) error {
+			runs++
+			if runs < 3 {
+				return fmt.Errorf("update: %w", serialization)
+			}
+			return nil
+		})
+		noError(t, err)
+		equal(t, 3, runs)
+		equal(t, 3, len(db.txs))
+		equal(t, true, db.txs[0].rolled)
+		equal(t, true, db.txs[2].committed)
+	})
+
+	t.Run("backoff", func(t *testing.T) {
+		p := &RetryPolicy{MinBackoff: 10 * time.Millisecond, MaxBackoff: 30 * time.Millisecond}
+		for attempt, max := range []time.Duration{10, 20, 30, 30} {
+			d := backoff(p, attempt+1)
+			max *= time.Millisecond
+			equal(t, true, d >= max/2 && d <= max)
+		}
+	})
+}
+
+func TestRetrySerializable(t *testing.T) {
+	ctx := context.Background()
+	pool, err := pgxpool.Connect(ctx, connString)
+	noError(t, err)
+	defer pool.Close()
+
+	runs := 0
+	err = InTxRetry(ctx, pool, pgx.TxOptions{IsoLevel: pgx.Serializable}, RetryPolicy{MaxAttempts: 2}, func(ctx context.Context) error {
+		runs++
+		if runs == 1 {
+			tx, _ := TxFromContext(ctx)
+			_, err := tx.Exec(ctx, "DO $$ BEGIN RAISE EXCEPTION 'conflict' USING ERRCODE = '40001'; END $$")
+			return err
+		}
+		var n int
+		return Get(ctx, pool, &n, "SELECT 1")
+	})
+	noError(t, err)
+	equal(t, 2, runs)
+}
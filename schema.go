@@ -0,0 +1,157 @@
+package easyscan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/popovpsk/easyscan/internal/core"
+)
+
+// SchemaProblem is a difference between a struct and the table it is mapped to.
+type SchemaProblem struct {
+	Type  reflect.Type
+	Table string
+	// Column is empty for problems of the whole table.
+	Column string
+	// Field is the path of the struct field, empty when the column has no field.
+	Field   string
+	Message string
+}
+
+// SchemaError lists every difference found by CheckSchema.
+type SchemaError struct {
+	Problems []SchemaProblem
+}
+
+func (e *SchemaError) Error() string {
+	var sb strings.Builder
+	sb.WriteString("schema mismatch:")
+	for _, p := range e.Problems {
+		sb.WriteString("\n\t")
+		sb.WriteString(p.Table)
+		if p.Column != "" {
+			sb.WriteByte('.')
+			sb.WriteString(p.Column)
+		}
+		if p.Field != "" {
+			sb.WriteString(" (")
+			sb.WriteString(p.Field)
+			sb.WriteByte(')')
+		}
+		sb.WriteString(": ")
+		sb.WriteString(p.Message)
+	}
+	return sb.String()
+}
+
+// catalogColumn is a column of a table as described by pg_attribute.
+type catalogColumn struct {
+	Name     string `db:"name"`
+	OID      uint32 `db:"oid"`
+	TypeName string `db:"type_name"`
+	Nullable bool   `db:"nullable"`
+}
+
+const catalogColumnsQuery = `SELECT a.attname AS name, a.atttypid AS oid, format_type(a.atttypid, a.atttypmod) AS type_name, NOT a.attnotnull AS nullable
+FROM pg_attribute a
+WHERE a.attrelid = to_regclass($1) AND a.attnum > 0 AND NOT a.attisdropped
+ORDER BY a.attnum`
+
+// CheckSchema compares the tagged fields of each struct type with the columns of its table, e.g.
+// map[reflect.Type]string{reflect.TypeOf(User{}): "public.users"}, and returns a *SchemaError listing
+// columns missing on either side, non pointer fields of nullable columns and incompatible types.
+// It is meant to run in CI or at startup to catch migrations that broke the structs.
+func CheckSchema(ctx context.Context, conn Querier, tables map[reflect.Type]string) error {
+	if conn == nil {
+		return errors.New("conn is nil")
+	}
+
+	types := make([]reflect.Type, 0, len(tables))
+	for t := range tables {
+		types = append(types, t)
+	}
+	sort.Slice(types, func(i, j int) bool {
+		return tables[types[i]] < tables[types[j]]
+	})
+
+	var problems []SchemaProblem
+	for _, t := range types {
+		table := tables[t]
+
+		rowType := t
+		for rowType.Kind() == reflect.Ptr {
+			rowType = rowType.Elem()
+		}
+		if rowType.Kind() != reflect.Struct {
+			return fmt.Errorf("%s: expected a struct but got %s", t, rowType.Kind())
+		}
+
+		var columns []catalogColumn
+		err := Select(ctx, conn, &columns, catalogColumnsQuery, table)
+		if err != nil {
+			return fmt.Errorf("columns of %s: %w", table, err)
+		}
+
+		problems = append(problems, compareSchema(rowType, table, columns)...)
+	}
+
+	if len(problems) > 0 {
+		return &SchemaError{Problems: problems}
+	}
+	return nil
+}
+
+func compareSchema(t reflect.Type, table string, columns []catalogColumn) []SchemaProblem {
+	if len(columns) == 0 {
+		return []SchemaProblem{{Type: t, Table: table, Message: "table doesn't exist"}}
+	}
+
+	var problems []SchemaProblem
+	add := func(column, field, format string, args ...interface{}) {
+		problems = append(problems, SchemaProblem{Type: t, Table: table, Column: column, Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	byName := make(map[string]catalogColumn, len(columns))
+	for _, c := range columns {
+		byName[c.Name] = c
+	}
+
+	fields := core.Fields(t)
+	mapped := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		mapped[f.Name] = true
+		path, ft := f.Path(t), f.Type(t)
+
+		c, ok := byName[f.Name]
+		if !ok {
+			add(f.Name, path, "column doesn't exist")
+			continue
+		}
+
+		if f.HasOption(core.TagJSON) {
+			if !core.IsJSONType(core.ColumnType{OID: c.OID, Name: c.TypeName}) {
+				add(c.Name, path, "%s isn't json", c.TypeName)
+			}
+			continue
+		}
+
+		if c.Nullable && !core.AcceptsNull(ft) && !f.HasOption(core.TagNullZero) && !f.HasDefault() {
+			add(c.Name, path, "column is nullable but %s can't hold NULL", ft)
+		}
+		if !core.CompatibleType(c.OID, ft) {
+			add(c.Name, path, "%s can't be scanned into %s", c.TypeName, ft)
+		}
+	}
+
+	for _, c := range columns {
+		if !mapped[c.Name] {
+			add(c.Name, "", "column has no field")
+		}
+	}
+
+	return problems
+}
@@ -0,0 +1,81 @@
+package easyscan
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type schemaAudit struct {
+	CreatedAt time.Time `db:"created_at"`
+}
+
+type schemaUser struct {
+	ID       int64          `db:"id"`
+	Name     string         `db:"name"`
+	Nickname sql.NullString `db:"nickname"`
+	Balance  int32          `db:"balance"`
+	Removed  bool           `db:"removed"`
+	schemaAudit
+}
+
+func TestCompareSchema(t *testing.T) {
+	columns := []catalogColumn{
+		{Name: "id", OID: 20, TypeName: "bigint"},
+		{Name: "name", OID: 25, TypeName: "text", Nullable: true},
+		{Name: "nickname", OID: 25, TypeName: "text", Nullable: true},
+		{Name: "balance", OID: 1700, TypeName: "numeric(10,2)"},
+		{Name: "created_at", OID: 1184, TypeName: "timestamp with time zone", Nullable: true},
+		{Name: "email", OID: 25, TypeName: "text"},
+	}
+
+	problems := compareSchema(reflect.TypeOf(schemaUser{}), "users", columns)
+	err := &SchemaError{Problems: problems}
+	equal(t, `schema mismatch:
+	users.name (schemaUser.Name): column is nullable but string can't hold NULL
+	users.balance (schemaUser.Balance): numeric(10,2) can't be scanned into int32
+	users.removed (schemaUser.Removed): column doesn't exist
+	users.created_at (schemaUser.schemaAudit.CreatedAt): column is nullable but time.Time can't hold NULL
+	users.email: column has no field`, err.Error())
+
+	problems = compareSchema(reflect.TypeOf(schemaUser{}), "missing", nil)
+	equal(t, []SchemaProblem{{Type: reflect.TypeOf(schemaUser{}), Table: "missing", Message: "table doesn't exist"}}, problems)
+}
+
+func TestCheckSchema(t *testing.T) {
+	ctx := context.Background()
+	pool, err := pgxpool.Connect(ctx, connString)
+	noError(t, err)
+	defer pool.Close()
+
+	_, err = pool.Exec(ctx, `CREATE TABLE IF NOT EXISTS easy_scan_schema(
+		id bigint primary key,
+		name text not null,
+		nickname text,
+		balance numeric(10, 2) not null,
+		removed bool not null,
+		created_at timestamptz)`)
+	noError(t, err)
+	defer func() {
+		_, e := pool.Exec(ctx, "DROP TABLE easy_scan_schema")
+		if e != nil {
+			panic(e)
+		}
+	}()
+
+	err = CheckSchema(ctx, pool, map[reflect.Type]string{
+		reflect.TypeOf(schemaUser{}): "public.easy_scan_schema",
+		reflect.TypeOf(keyedUser{}):  "easy_scan_missing",
+	})
+	var se *SchemaError
+	equal(t, true, errors.As(err, &se))
+	equal(t, 3, len(se.Problems))
+	equal(t, "easy_scan_missing", se.Problems[0].Table)
+	equal(t, "balance", se.Problems[1].Column)
+	equal(t, "created_at", se.Problems[2].Column)
+}
@@ -4,163 +4,86 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"reflect"
 
-	"github.com/jackc/pgproto3/v2"
+	"github.com/jackc/pgtype"
 	"github.com/jackc/pgx/v4"
-)
-
-var emptyScanObj = emptyScan{}
-
-type emptyScan struct {
-}
 
-func (emptyScan) Scan(_ interface{}) error {
-	return nil
-}
+	"github.com/popovpsk/easyscan/internal/core"
+)
 
-type pgxExecutor interface {
-	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+// rowsAdapter adds column names to pgx.Rows.
+type rowsAdapter struct {
+	pgx.Rows
 }
 
-func Select(ctx context.Context, conn pgxExecutor, dest interface{}, query string, args ...interface{}) error {
-	if conn == nil {
-		return errors.New("conn is nil")
-	}
-
-	slicePtr := reflect.ValueOf(dest)
-
-	if slicePtr.Kind() != reflect.Ptr || slicePtr.IsNil() {
-		return errors.New("destination must be a non nil pointer to slice")
-	}
-
-	slice := slicePtr.Elem()
-
-	sliceType := slice.Type()
-	if sliceType.Kind() != reflect.Slice {
-		return fmt.Errorf("expected a slice but got %s", slice.Type().Kind())
+func (r rowsAdapter) Columns() []string {
+	fds := r.FieldDescriptions()
+	columns := make([]string, len(fds))
+	for i := range fds {
+		columns[i] = string(fds[i].Name)
 	}
+	return columns
+}
 
-	//example: is string, for dest = *[]string
-	sliceElemType := sliceType.Elem()
-
-	//[]*object or []object
-	isPtr := sliceElemType.Kind() == reflect.Ptr
-
-	exemplarType := sliceElemType
-	if isPtr {
-		exemplarType = exemplarType.Elem()
-	}
-
-	isSupported := isPgxSupportedType(exemplarType, true)
-	if exemplarType.Kind() != reflect.Struct && !isSupported {
-		return fmt.Errorf("expected a struct or a pointer to a struct in the slice but got %s", exemplarType.Kind())
-	}
-
-	rows, err := conn.Query(ctx, query, args...)
-	if err != nil {
-		return fmt.Errorf("query: %w", err)
-	}
-	defer rows.Close()
-
-	if isSupported {
-		return scanToSupported(rows, isPtr, slice, exemplarType)
+// ScanColumnIndex returns the failed column of a Scan error.
+func (r rowsAdapter) ScanColumnIndex(err error) (int, bool) {
+	var argErr pgx.ScanArgError
+	if errors.As(err, &argErr) {
+		return argErr.ColumnIndex, true
 	}
-
-	return scanObjects(rows, isPtr, slice, exemplarType)
+	return 0, false
 }
 
-func scanToSupported(rows pgx.Rows, isPtr bool, slice reflect.Value, exemplarType reflect.Type) error {
-	for rows.Next() {
-		exemplarPointer := reflect.New(exemplarType)
-
-		err := rows.Scan(exemplarPointer.Interface())
-		if err != nil {
-			return fmt.Errorf("rows.Scan: %w", err)
-		}
+// typeNames resolves the names of the built-in types.
+var typeNames = pgtype.NewConnInfo()
 
-		if isPtr {
-			addToSlice(slice, exemplarPointer)
-		} else {
-			addToSlice(slice, exemplarPointer.Elem())
+func (r rowsAdapter) ColumnTypes() []core.ColumnType {
+	fds := r.FieldDescriptions()
+	types := make([]core.ColumnType, len(fds))
+	for i := range fds {
+		types[i].OID = fds[i].DataTypeOID
+		if dt, ok := typeNames.DataTypeForOID(fds[i].DataTypeOID); ok {
+			types[i].Name = dt.Name
 		}
 	}
-	return rows.Err()
+	return types
+}
+
+func Select(ctx context.Context, conn Querier, dest interface{}, query string, args ...interface{}) error {
+	return selectRows(ctx, conn, dest, query, args, nil)
 }
 
-func scanObjects(rows pgx.Rows, isPtr bool, slice reflect.Value, exemplarType reflect.Type) error {
-	if !rows.Next() {
-		return rows.Err()
+// selectRows is Select that passes the rows through wrap, if not nil, before they are scanned.
+func selectRows(ctx context.Context, conn Querier, dest interface{}, query string, args []interface{}, wrap func(core.Rows) core.Rows) error {
+	if conn == nil {
+		return errors.New("conn is nil")
 	}
+	conn = txFromContext(ctx, conn)
 
-	objectForFilling := reflect.New(exemplarType)
-	scans, err := getSliceForScan(exemplarType, rows.FieldDescriptions(), objectForFilling)
+	slice, err := core.NewSlice(dest)
 	if err != nil {
 		return err
 	}
 
-	err = rows.Scan(scans...)
+	opts, args := core.ExtractOptions(args)
+
+	query, args, err = expandIn(query, args, opts.EmptyIn)
 	if err != nil {
-		return fmt.Errorf("rows.Scan: %w", err)
+		return err
 	}
 
-	for rows.Next() {
-		if isPtr {
-			exemplarPtr := reflect.New(exemplarType)
-			exemplarPtr.Elem().Set(objectForFilling.Elem())
-			addToSlice(slice, exemplarPtr)
-		} else {
-			addToSlice(slice, objectForFilling.Elem())
-		}
-
-		err = rows.Scan(scans...)
+	err = retry(ctx, retryPolicy(conn, opts), dest, func() error {
+		rows, err := conn.Query(ctx, query, args...)
 		if err != nil {
-			return fmt.Errorf("rows.Scan: %w", err)
+			return fmt.Errorf("query: %w", err)
 		}
-	}
-
-	if isPtr {
-		addToSlice(slice, objectForFilling)
-	} else {
-		addToSlice(slice, objectForFilling.Elem())
-	}
-
-	return rows.Err()
-}
+		defer rows.Close()
 
-func addToSlice(slice reflect.Value, element reflect.Value) {
-	l := slice.Len()
-	if l < slice.Cap() {
-		l++
-		slice.SetLen(l)
-
-		slice.Index(l - 1).Set(element)
-		return
-	}
-
-	slice.Set(reflect.Append(slice, element))
-}
-
-func getSliceForScan(t reflect.Type, fieldDescriptions []pgproto3.FieldDescription, exemplarPointer reflect.Value) ([]interface{}, error) {
-	scans := make([]interface{}, len(fieldDescriptions))
-
-	tags := getTaggedFields(t)
-
-	e := exemplarPointer.Elem()
-
-	matchingFailed := true
-
-	for idx, fd := range fieldDescriptions {
-		s := tags.find(fd.Name, e)
-		if s != emptyScanObj {
-			matchingFailed = false
+		var src core.Rows = rowsAdapter{rows}
+		if wrap != nil {
+			src = wrap(src)
 		}
-		scans[idx] = s
-	}
-
-	if matchingFailed {
-		return nil, errors.New("db tags have no matches to columns")
-	}
-
-	return scans, nil
+		return slice.Scan(src, opts)
+	})
+	return wrapPgError(err)
 }
@@ -50,6 +50,28 @@ func TestSelect(t *testing.T) {
 		}
 	})
 
+	t.Run("append mode", func(t *testing.T) {
+		result := []int{42}
+		err = Select(ctx, pool, &result, "SELECT generate_series(0, 2)")
+		noError(t, err)
+		equal(t, []int{42, 0, 1, 2}, result)
+	})
+
+	t.Run("replace mode", func(t *testing.T) {
+		result := []int{42, 43, 44, 45}
+		err = Select(ctx, pool, &result, "SELECT generate_series(0, $1::int)", WithMode(ModeReplace), 1)
+		noError(t, err)
+		equal(t, []int{0, 1}, result)
+	})
+
+	t.Run("expected rows", func(t *testing.T) {
+		result := []int{42}
+		err = Select(ctx, pool, &result, "SELECT generate_series(0, 9)", WithExpectedRows(100))
+		noError(t, err)
+		equal(t, 11, len(result))
+		equal(t, 101, cap(result))
+	})
+
 	t.Run("named types", func(t *testing.T) {
 		type namedType string
 		var result []namedType
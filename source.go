@@ -0,0 +1,63 @@
+package easyscan
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v4"
+
+	"github.com/popovpsk/easyscan/internal/core"
+)
+
+// RowSource is a driver agnostic result set.
+// Any implementation can be scanned into tagged structs by ScanOne and ScanAll.
+type RowSource interface {
+	Next() bool
+	Columns() []string
+	Scan(dest ...interface{}) error
+	Err() error
+}
+
+var _ core.Rows = RowSource(nil)
+
+// ScanOne reads exactly one row from src into dest, the same way Get does.
+// It returns pgx.ErrNoRows when src is empty and ErrMoreThanOneRow when src has more rows.
+func ScanOne(src RowSource, dest interface{}, opts ...Option) error {
+	if src == nil {
+		return errors.New("source is nil")
+	}
+
+	row, err := core.NewRow(dest)
+	if err != nil {
+		return err
+	}
+
+	var o core.Options
+	for _, opt := range opts {
+		opt.Apply(&o)
+	}
+
+	err = row.Scan(src, o)
+	if err == core.ErrNoRows {
+		return pgx.ErrNoRows
+	}
+	return err
+}
+
+// ScanAll reads all rows from src into the slice pointed by dest, the same way Select does.
+func ScanAll(src RowSource, dest interface{}, opts ...Option) error {
+	if src == nil {
+		return errors.New("source is nil")
+	}
+
+	slice, err := core.NewSlice(dest)
+	if err != nil {
+		return err
+	}
+
+	var o core.Options
+	for _, opt := range opts {
+		opt.Apply(&o)
+	}
+
+	return slice.Scan(src, o)
+}
@@ -0,0 +1,50 @@
+package easyscan
+
+import (
+	"testing"
+
+	"github.com/jackc/pgx/v4"
+)
+
+func TestScanOne(t *testing.T) {
+	type user struct {
+		ID int `db:"id"`
+	}
+
+	t.Run("struct", func(t *testing.T) {
+		var result user
+		err := ScanOne(rowsAdapter{newFakeRows([]string{"id"}, []interface{}{1})}, &result)
+		noError(t, err)
+		equal(t, user{ID: 1}, result)
+	})
+
+	t.Run("options", func(t *testing.T) {
+		result := user{ID: 10}
+		err := ScanOne(rowsAdapter{newFakeRows([]string{"id"}, []interface{}{nil})}, &result, WithNullZero())
+		noError(t, err)
+		equal(t, user{}, result)
+	})
+
+	t.Run("no rows", func(t *testing.T) {
+		var result user
+		err := ScanOne(rowsAdapter{newFakeRows([]string{"id"})}, &result)
+		equal(t, pgx.ErrNoRows, err)
+	})
+
+	t.Run("nil source", func(t *testing.T) {
+		var result user
+		err := ScanOne(nil, &result)
+		errorContains(t, err, "source is nil")
+	})
+}
+
+func TestScanAll(t *testing.T) {
+	type user struct {
+		ID int `db:"id"`
+	}
+
+	result := []user{{ID: 10}}
+	err := ScanAll(rowsAdapter{newFakeRows([]string{"id"}, []interface{}{1}, []interface{}{2})}, &result, WithMode(ModeReplace))
+	noError(t, err)
+	equal(t, []user{{ID: 1}, {ID: 2}}, result)
+}
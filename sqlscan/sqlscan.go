@@ -0,0 +1,142 @@
+// Package sqlscan provides Get and Select for database/sql.
+// It shares the struct mapping with the root package, so a type scans identically
+// with pgx and with any database/sql driver.
+//
+// In arguments, WithEmptyIn and WithRetry of the root package are rejected with an error.
+package sqlscan
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/popovpsk/easyscan/internal/core"
+)
+
+var ErrMoreThanOneRow = core.ErrMoreThanOneRow
+
+// JSONCodec decodes fields tagged with json. It is the same type as easyscan.JSONCodec.
+type JSONCodec = core.JSONCodec
+
+// ScanError is a failure to scan a column. It is the same type as easyscan.ScanError.
+type ScanError = core.ScanError
+
+// Querier is the source of rows for Get and Select.
+// *sql.DB, *sql.Tx and *sql.Conn implement it.
+type Querier interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+var (
+	_ Querier = (*sql.DB)(nil)
+	_ Querier = (*sql.Tx)(nil)
+	_ Querier = (*sql.Conn)(nil)
+)
+
+// Option changes the behavior of a single Get or Select call.
+// It is the same type as easyscan.Option.
+type Option = core.Option
+
+// Mode defines what Select does with rows already present in the destination slice.
+type Mode = core.Mode
+
+const (
+	// ModeAppend appends scanned rows after the existing elements. This is the default.
+	ModeAppend = core.ModeAppend
+	// ModeReplace discards the existing elements before the first row is added.
+	ModeReplace = core.ModeReplace
+)
+
+// TypeMismatchError lists every incompatible column found by WithTypeCheck. It is the same type as easyscan.TypeMismatchError.
+type TypeMismatchError = core.TypeMismatchError
+
+// The options are shared with the root package, an option of either package works with both.
+var (
+	// WithMode sets the Mode of Select.
+	WithMode = core.WithMode
+	// WithExpectedRows makes Select reserve capacity for n rows before scanning.
+	WithExpectedRows = core.WithExpectedRows
+	// WithTypeCheck makes Get and Select compare the type of every column with the type of its destination
+	// before the first row is scanned. database/sql reports no OIDs, so the types are matched
+	// by their Postgres names and columns of other types aren't checked.
+	WithTypeCheck = core.WithTypeCheck
+	// WithNullZero makes NULL scan as the zero value into every field that can't hold NULL.
+	WithNullZero = core.WithNullZero
+	// WithJSONCodec sets the codec that decodes fields tagged with json, encoding/json is used by default.
+	WithJSONCodec = core.WithJSONCodec
+)
+
+// rowsAdapter hides the error of Columns, it fails only for closed rows,
+// and core asks for columns right after a successful Next.
+type rowsAdapter struct {
+	*sql.Rows
+}
+
+func (r rowsAdapter) Columns() []string {
+	columns, _ := r.Rows.Columns()
+	return columns
+}
+
+// ColumnTypes returns the type names reported by the driver, database/sql has no OIDs.
+func (r rowsAdapter) ColumnTypes() []core.ColumnType {
+	cts, _ := r.Rows.ColumnTypes()
+	types := make([]core.ColumnType, len(cts))
+	for i := range cts {
+		types[i].Name = strings.ToLower(cts[i].DatabaseTypeName())
+	}
+	return types
+}
+
+func Get(ctx context.Context, conn Querier, dest interface{}, query string, args ...interface{}) error {
+	if conn == nil {
+		return errors.New("conn is nil")
+	}
+
+	row, err := core.NewRow(dest)
+	if err != nil {
+		return err
+	}
+
+	opts, args := core.ExtractOptions(args)
+	if err = core.CheckRootOnly("sqlscan", opts, args); err != nil {
+		return err
+	}
+
+	rows, err := conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("query: %w", err)
+	}
+	defer rows.Close()
+
+	err = row.Scan(rowsAdapter{rows}, opts)
+	if err == core.ErrNoRows {
+		return sql.ErrNoRows
+	}
+	return err
+}
+
+func Select(ctx context.Context, conn Querier, dest interface{}, query string, args ...interface{}) error {
+	if conn == nil {
+		return errors.New("conn is nil")
+	}
+
+	slice, err := core.NewSlice(dest)
+	if err != nil {
+		return err
+	}
+
+	opts, args := core.ExtractOptions(args)
+	if err = core.CheckRootOnly("sqlscan", opts, args); err != nil {
+		return err
+	}
+
+	rows, err := conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("query: %w", err)
+	}
+	defer rows.Close()
+
+	return slice.Scan(rowsAdapter{rows}, opts)
+}
@@ -0,0 +1,157 @@
+package sqlscan
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	_ "github.com/jackc/pgx/v4/stdlib"
+)
+
+const connString = "user=postgres password=postgres host=localhost dbname=easyscan port=5432"
+
+type RowChanges struct {
+	CreatedAt time.Time  `db:"created_at"`
+	UpdatedAt *time.Time `db:"updated_at"`
+}
+
+type person struct {
+	ID   int     `db:"id"`
+	Name *string `db:"name"`
+	RowChanges
+}
+
+func TestGet(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	db, err := sql.Open("pgx", connString)
+	noError(t, err)
+	defer db.Close()
+
+	t.Run("primitive", func(t *testing.T) {
+		var result int
+		err = Get(ctx, db, &result, "SELECT 1")
+		noError(t, err)
+		equal(t, 1, result)
+	})
+
+	t.Run("embedded struct", func(t *testing.T) {
+		var result person
+		err = Get(ctx, db, &result, `SELECT 10 as id, NULL::text as name, '2012-03-04 10:11:12'::timestamp as created_at, 1 as extra`)
+		noError(t, err)
+		equal(t, 10, result.ID)
+		equal(t, (*string)(nil), result.Name)
+		equal(t, time.Date(2012, 3, 4, 10, 11, 12, 0, time.UTC), result.CreatedAt.UTC())
+	})
+
+	t.Run("tx", func(t *testing.T) {
+		tx, err := db.BeginTx(ctx, nil)
+		noError(t, err)
+		defer tx.Rollback()
+
+		var result string
+		err = Get(ctx, tx, &result, "SELECT $1::text", "foo")
+		noError(t, err)
+		equal(t, "foo", result)
+	})
+
+	t.Run("no rows", func(t *testing.T) {
+		var result int
+		err = Get(ctx, db, &result, "SELECT 1 WHERE 1=0")
+		equal(t, true, errors.Is(err, sql.ErrNoRows))
+	})
+
+	t.Run("more than 1 rows", func(t *testing.T) {
+		var result int
+		err = Get(ctx, db, &result, "SELECT generate_series(0, 9)")
+		equal(t, ErrMoreThanOneRow, err)
+	})
+
+	t.Run("nil conn", func(t *testing.T) {
+		var result int
+		err = Get(ctx, nil, &result, "SELECT 1")
+		errorContains(t, err, "conn is nil")
+	})
+}
+
+func TestSelect(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	db, err := sql.Open("pgx", connString)
+	noError(t, err)
+	defer db.Close()
+
+	t.Run("primitive", func(t *testing.T) {
+		var result []int
+		err = Select(ctx, db, &result, "SELECT generate_series(0, 9)")
+		noError(t, err)
+		equal(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, result)
+	})
+
+	t.Run("conn", func(t *testing.T) {
+		conn, err := db.Conn(ctx)
+		noError(t, err)
+		defer conn.Close()
+
+		var result []string
+		err = Select(ctx, conn, &result, "SELECT * FROM UNNEST(ARRAY['foo', 'bar'])")
+		noError(t, err)
+		equal(t, []string{"foo", "bar"}, result)
+	})
+
+	t.Run("pointers to structs", func(t *testing.T) {
+		result := []*person{{ID: 42}}
+		err = Select(ctx, db, &result, `SELECT generate_series(1, 3) as id, 'foo' as name`, WithMode(ModeReplace))
+		noError(t, err)
+		equal(t, 3, len(result))
+		for i, p := range result {
+			equal(t, i+1, p.ID)
+			equal(t, "foo", *p.Name)
+		}
+	})
+
+	t.Run("no matches", func(t *testing.T) {
+		var result []person
+		err = Select(ctx, db, &result, "SELECT 1 as foo")
+		errorContains(t, err, "have no matches to columns")
+	})
+}
+
+func equal(t *testing.T, l, r interface{}) {
+	if !reflect.DeepEqual(l, r) {
+		fmt.Printf("%v not equal %v\n", js(l), js(r))
+		t.Fail()
+	}
+}
+
+func js(v interface{}) string {
+	result, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	return string(result)
+}
+
+func noError(t *testing.T, err error) {
+	if err != nil {
+		t.Fail()
+		panic(err)
+	}
+}
+
+func errorContains(t *testing.T, err error, str string) {
+	if err == nil {
+		t.Fail()
+		panic("error is nil")
+	}
+	if !strings.Contains(err.Error(), str) {
+		t.Fail()
+		panic(fmt.Sprintf("error %v does not contains %s", err, str))
+	}
+}
@@ -0,0 +1,109 @@
+package easyscan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// TxBeginner starts transactions.
+// *pgx.Conn, *pgxpool.Pool and *pgxpool.Conn implement it.
+type TxBeginner interface {
+	BeginTx(ctx context.Context, txOptions pgx.TxOptions) (pgx.Tx, error)
+}
+
+var (
+	_ TxBeginner = (*pgx.Conn)(nil)
+	_ TxBeginner = (*pgxpool.Pool)(nil)
+	_ TxBeginner = (*pgxpool.Conn)(nil)
+)
+
+type txKey struct{}
+
+// txState is the transaction InTx stores in the context.
+type txState struct {
+	// db is the TxBeginner the transaction was started on
+	db interface{}
+	tx pgx.Tx
+}
+
+// TxFromContext returns the transaction started by InTx, if any.
+func TxFromContext(ctx context.Context) (pgx.Tx, bool) {
+	s, ok := ctx.Value(txKey{}).(*txState)
+	if !ok {
+		return nil, false
+	}
+	return s.tx, true
+}
+
+// InTx runs fn in a transaction started on db with opts.
+// The transaction is committed when fn returns nil and rolled back when it returns an error or panics,
+// the panic is raised again after the rollback.
+//
+// The context passed to fn carries the transaction: Get, Select and the other functions of the package
+// called with db and that context run in the transaction instead of db.
+// InTx called with such a context creates a savepoint in the current transaction and ignores opts.
+func InTx(ctx context.Context, db TxBeginner, opts pgx.TxOptions, fn func(ctx context.Context) error) (err error) {
+	if db == nil {
+		return errors.New("db is nil")
+	}
+
+	var tx pgx.Tx
+	if parent, ok := ctx.Value(txKey{}).(*txState); ok && parent.owns(db) {
+		tx, err = parent.tx.Begin(ctx)
+		if err != nil {
+			return fmt.Errorf("savepoint: %w", err)
+		}
+	} else {
+		tx, err = db.BeginTx(ctx, opts)
+		if err != nil {
+			return fmt.Errorf("begin: %w", err)
+		}
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback(ctx)
+			panic(p)
+		}
+	}()
+
+	err = fn(context.WithValue(ctx, txKey{}, &txState{db: db, tx: tx}))
+	if err != nil {
+		if rbErr := tx.Rollback(ctx); rbErr != nil {
+			return fmt.Errorf("%w; rollback: %v", err, rbErr)
+		}
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	return nil
+}
+
+// owns reports whether conn is the TxBeginner the transaction was started on.
+func (s *txState) owns(conn interface{}) bool {
+	t := reflect.TypeOf(conn)
+	if t == nil || !t.Comparable() {
+		return false
+	}
+	return conn == s.db
+}
+
+// txFromContext returns the transaction of ctx when it was started on conn, otherwise conn.
+func txFromContext[T any](ctx context.Context, conn T) T {
+	s, ok := ctx.Value(txKey{}).(*txState)
+	if !ok || !s.owns(conn) {
+		return conn
+	}
+	if tx, ok := s.tx.(T); ok {
+		return tx
+	}
+	return conn
+}
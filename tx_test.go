@@ -0,0 +1,172 @@
+package easyscan
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// fakeTx records how the transaction ended, unimplemented methods of pgx.Tx panic.
+type fakeTx struct {
+	pgx.Tx
+	rows      *fakeRows
+	parent    *fakeTx
+	nested    []*fakeTx
+	committed bool
+	rolled    bool
+}
+
+func (tx *fakeTx) Begin(context.Context) (pgx.Tx, error) {
+	n := &fakeTx{rows: tx.rows, parent: tx}
+	tx.nested = append(tx.nested, n)
+	return n, nil
+}
+
+func (tx *fakeTx) Commit(context.Context) error {
+	tx.committed = true
+	return nil
+}
+
+func (tx *fakeTx) Rollback(context.Context) error {
+	if !tx.committed {
+		tx.rolled = true
+	}
+	return nil
+}
+
+func (tx *fakeTx) Query(context.Context, string, ...interface{}) (pgx.Rows, error) {
+	return tx.rows, nil
+}
+
+// fakeDB starts fakeTx transactions, its own Query fails.
+type fakeDB struct {
+	txs  []*fakeTx
+	rows *fakeRows
+}
+
+func (db *fakeDB) BeginTx(context.Context, pgx.TxOptions) (pgx.Tx, error) {
+	tx := &fakeTx{rows: db.rows}
+	db.txs = append(db.txs, tx)
+	return tx, nil
+}
+
+func (db *fakeDB) Query(context.Context, string, ...interface{}) (pgx.Rows, error) {
+	return nil, errors.New("query outside of the transaction")
+}
+
+func TestInTxFlow(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("commit", func(t *testing.T) {
+		db := &fakeDB{rows: newFakeRows([]string{"n"}, []interface{}{7})}
+		var result int
+		err := InTx(ctx, db, pgx.TxOptions{}, func(ctx context.Context) error {
+			_, ok := TxFromContext(ctx)
+			equal(t, true, ok)
+			return Get(ctx, db, &result, "SELECT 7")
+		})
+		noError(t, err)
+		equal(t, 7, result)
+		equal(t, true, db.txs[0].committed)
+
+		err = Get(ctx, db, &result, "SELECT 7")
+		errorContains(t, err, "outside of the transaction")
+	})
+
+	t.Run("rollback", func(t *testing.T) {
+		db := &fakeDB{}
+		fnErr := errors.New("fn failed")
+		err := InTx(ctx, db, pgx.TxOptions{}, func(ctx context.Context) error {
+			return fnErr
+		})
+		equal(t, fnErr, err)
+		equal(t, true, db.txs[0].rolled)
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		db := &fakeDB{}
+		defer func() {
+			equal(t, "boom", recover())
+			equal(t, true, db.txs[0].rolled)
+		}()
+		_ = InTx(ctx, db, pgx.TxOptions{}, func(ctx context.Context) error {
+			panic("boom")
+		})
+	})
+
+	t.Run("savepoint", func(t *testing.T) {
+		db := &fakeDB{}
+		err := InTx(ctx, db, pgx.TxOptions{}, func(ctx context.Context) error {
+			inner := InTx(ctx, db, pgx.TxOptions{}, func(ctx context.Context) error {
+				return errors.New("inner failed")
+			})
+			errorContains(t, inner, "inner failed")
+			return nil
+		})
+		noError(t, err)
+		equal(t, 1, len(db.txs))
+		equal(t, true, db.txs[0].committed)
+		equal(t, true, db.txs[0].nested[0].rolled)
+	})
+
+	t.Run("other db", func(t *testing.T) {
+		db := &fakeDB{}
+		other := &fakeDB{rows: newFakeRows([]string{"n"}, []interface{}{1})}
+		err := InTx(ctx, db, pgx.TxOptions{}, func(ctx context.Context) error {
+			var result int
+			return Get(ctx, other, &result, "SELECT 1")
+		})
+		errorContains(t, err, "outside of the transaction")
+	})
+}
+
+func TestInTx(t *testing.T) {
+	ctx := context.Background()
+	pool, err := pgxpool.Connect(ctx, connString)
+	noError(t, err)
+	defer pool.Close()
+
+	_, err = pool.Exec(ctx, "CREATE TABLE IF NOT EXISTS easy_scan_tx(id int primary key)")
+	noError(t, err)
+	defer func() {
+		_, e := pool.Exec(ctx, "DROP TABLE easy_scan_tx")
+		if e != nil {
+			panic(e)
+		}
+	}()
+
+	err = InTx(ctx, pool, pgx.TxOptions{}, func(ctx context.Context) error {
+		_, err := ExecNamed(ctx, pool, "INSERT INTO easy_scan_tx(id) VALUES (:id)", map[string]interface{}{"id": 1})
+		if err != nil {
+			return err
+		}
+
+		// the savepoint is rolled back, the outer insert stays
+		_ = InTx(ctx, pool, pgx.TxOptions{}, func(ctx context.Context) error {
+			_, err := ExecNamed(ctx, pool, "INSERT INTO easy_scan_tx(id) VALUES (:id)", map[string]interface{}{"id": 2})
+			noError(t, err)
+			return errors.New("rollback")
+		})
+
+		var ids []int
+		err = Select(ctx, pool, &ids, "SELECT id FROM easy_scan_tx")
+		equal(t, []int{1}, ids)
+		return err
+	})
+	noError(t, err)
+
+	err = InTx(ctx, pool, pgx.TxOptions{}, func(ctx context.Context) error {
+		_, err := ExecNamed(ctx, pool, "INSERT INTO easy_scan_tx(id) VALUES (:id)", map[string]interface{}{"id": 3})
+		noError(t, err)
+		return errors.New("rollback")
+	})
+	errorContains(t, err, "rollback")
+
+	var ids []int
+	err = Select(ctx, pool, &ids, "SELECT id FROM easy_scan_tx ORDER BY id")
+	noError(t, err)
+	equal(t, []int{1}, ids)
+}
@@ -0,0 +1,251 @@
+package easyscan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v4"
+
+	"github.com/popovpsk/easyscan/internal/core"
+)
+
+const (
+	// tagGenerated marks a column computed by the database, e.g. serial or GENERATED.
+	// It is never written and is read back by RETURNING.
+	tagGenerated = "generated"
+	// tagReadonly marks a column that is written by Insert only and never updated,
+	// e.g. created_at or owner_id.
+	tagReadonly = "readonly"
+)
+
+// Insert inserts row into table and scans the inserted row back into it.
+// row must be a pointer to a struct, the columns and values come from its db tags.
+func Insert(ctx context.Context, conn Querier, table string, row interface{}) error {
+	v, fields, err := writeTarget(row)
+	if err != nil {
+		return err
+	}
+
+	b := &strings.Builder{}
+	args := insertStatement(b, table, v, fields)
+	writeReturning(b, fields)
+
+	return Get(ctx, conn, row, b.String(), args...)
+}
+
+// Update updates the rows of table matching where and scans the updated row back into row.
+// where may reference fields of row by :name placeholders, e.g. "id = :id",
+// and must match a single row.
+//
+// The statement runs in a savepoint of a transaction, or in its own transaction when conn can begin one,
+// so a where matching several rows changes nothing and ErrMoreThanOneRow is returned.
+// Other queriers, e.g. QuerierFunc, can't undo the statement, the rows are updated before the error is returned.
+func Update(ctx context.Context, conn Querier, table string, row interface{}, where string) error {
+	v, fields, err := writeTarget(row)
+	if err != nil {
+		return err
+	}
+	if where == "" {
+		return errors.New("update: where is empty")
+	}
+
+	whereSQL, args, err := BindNamed(where, row)
+	if err != nil {
+		return err
+	}
+
+	b := &strings.Builder{}
+	b.WriteString("UPDATE ")
+	b.WriteString(quoteTable(table))
+	b.WriteString(" SET ")
+
+	set := 0
+	for _, f := range fields {
+		if f.HasOption(tagGenerated) || f.HasOption(tagReadonly) {
+			continue
+		}
+		if set > 0 {
+			b.WriteString(", ")
+		}
+		args = append(args, f.Value(v).Interface())
+		b.WriteString(quoteIdentifier(f.Name))
+		b.WriteString(" = $")
+		b.WriteString(strconv.Itoa(len(args)))
+		set++
+	}
+	if set == 0 {
+		return fmt.Errorf("update: %s has no updatable fields", v.Type())
+	}
+
+	b.WriteString(" WHERE ")
+	b.WriteString(whereSQL)
+	writeReturning(b, fields)
+
+	if conn == nil {
+		return errors.New("conn is nil")
+	}
+	return atomically(ctx, txFromContext(ctx, conn), func(q Querier) error {
+		return Get(ctx, q, row, b.String(), args...)
+	})
+}
+
+// atomically runs fn in a savepoint when conn is a transaction and in a new transaction when conn can begin one,
+// so that the changes of a failed fn are rolled back. Other queriers are passed to fn as is.
+func atomically(ctx context.Context, conn Querier, fn func(q Querier) error) error {
+	switch c := conn.(type) {
+	case pgx.Tx:
+		sp, err := c.Begin(ctx)
+		if err != nil {
+			return fmt.Errorf("savepoint: %w", err)
+		}
+		if err = fn(sp); err != nil {
+			if rbErr := sp.Rollback(ctx); rbErr != nil {
+				return fmt.Errorf("%w; rollback: %v", err, rbErr)
+			}
+			return err
+		}
+		if err = sp.Commit(ctx); err != nil {
+			return fmt.Errorf("release savepoint: %w", err)
+		}
+		return nil
+
+	case TxBeginner:
+		return InTx(ctx, c, pgx.TxOptions{}, func(ctx context.Context) error {
+			tx, _ := TxFromContext(ctx)
+			return fn(tx)
+		})
+	}
+	return fn(conn)
+}
+
+// Upsert inserts row into table, or updates the existing row on a conflict over conflictColumns,
+// and scans the resulting row back into row.
+func Upsert(ctx context.Context, conn Querier, table string, row interface{}, conflictColumns ...string) error {
+	v, fields, err := writeTarget(row)
+	if err != nil {
+		return err
+	}
+	if len(conflictColumns) == 0 {
+		return errors.New("upsert: conflict columns are empty")
+	}
+
+	b := &strings.Builder{}
+	args := insertStatement(b, table, v, fields)
+
+	b.WriteString(" ON CONFLICT (")
+	for i, c := range conflictColumns {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(quoteIdentifier(c))
+	}
+	b.WriteString(") DO UPDATE SET ")
+
+	set := 0
+	for _, f := range fields {
+		if f.HasOption(tagGenerated) || f.HasOption(tagReadonly) || contains(conflictColumns, f.Name) {
+			continue
+		}
+		if set > 0 {
+			b.WriteString(", ")
+		}
+		writeExcluded(b, f.Name)
+		set++
+	}
+	if set == 0 {
+		// DO NOTHING returns no rows on a conflict, a no-op update keeps RETURNING working.
+		writeExcluded(b, conflictColumns[0])
+	}
+
+	writeReturning(b, fields)
+
+	return Get(ctx, conn, row, b.String(), args...)
+}
+
+func writeTarget(row interface{}) (reflect.Value, []core.Field, error) {
+	v := reflect.ValueOf(row)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return reflect.Value{}, nil, errors.New("row must be a non nil pointer to a struct")
+	}
+
+	v = v.Elem()
+	fields := core.Fields(v.Type())
+	if len(fields) == 0 {
+		return reflect.Value{}, nil, fmt.Errorf("%s has no db tags", v.Type())
+	}
+
+	return v, fields, nil
+}
+
+// insertStatement writes INSERT INTO ... VALUES ... and returns its arguments.
+func insertStatement(b *strings.Builder, table string, v reflect.Value, fields []core.Field) []interface{} {
+	b.WriteString("INSERT INTO ")
+	b.WriteString(quoteTable(table))
+
+	args := make([]interface{}, 0, len(fields))
+	values := &strings.Builder{}
+	for _, f := range fields {
+		if f.HasOption(tagGenerated) {
+			continue
+		}
+		if len(args) == 0 {
+			b.WriteString(" (")
+		} else {
+			b.WriteString(", ")
+			values.WriteString(", ")
+		}
+		args = append(args, f.Value(v).Interface())
+		b.WriteString(quoteIdentifier(f.Name))
+		values.WriteByte('$')
+		values.WriteString(strconv.Itoa(len(args)))
+	}
+
+	if len(args) == 0 {
+		b.WriteString(" DEFAULT VALUES")
+		return args
+	}
+
+	b.WriteString(") VALUES (")
+	b.WriteString(values.String())
+	b.WriteByte(')')
+	return args
+}
+
+func writeExcluded(b *strings.Builder, column string) {
+	c := quoteIdentifier(column)
+	b.WriteString(c)
+	b.WriteString(" = EXCLUDED.")
+	b.WriteString(c)
+}
+
+func writeReturning(b *strings.Builder, fields []core.Field) {
+	b.WriteString(" RETURNING ")
+	for i, f := range fields {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(quoteIdentifier(f.Name))
+	}
+}
+
+func quoteIdentifier(name string) string {
+	return pgx.Identifier{name}.Sanitize()
+}
+
+// quoteTable quotes a table name that may be qualified by a schema, e.g. "public.users".
+func quoteTable(table string) string {
+	return pgx.Identifier(strings.Split(table, ".")).Sanitize()
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
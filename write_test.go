@@ -0,0 +1,178 @@
+package easyscan
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type writeAudit struct {
+	CreatedAt time.Time `db:"created_at,readonly"`
+}
+
+type writeRow struct {
+	ID   int64  `db:"id,generated"`
+	Name string `db:"name"`
+	Age  int    `db:"age"`
+	writeAudit
+}
+
+// captureQuerier records the query and returns the given rows.
+type captureQuerier struct {
+	sql  string
+	args []interface{}
+	rows *fakeRows
+}
+
+func (q *captureQuerier) Query(_ context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	q.sql, q.args = sql, args
+	return q.rows, nil
+}
+
+func TestWriteStatements(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2012, 3, 4, 10, 11, 12, 0, time.UTC)
+	returned := func() *fakeRows {
+		return newFakeRows([]string{"id", "name", "age", "created_at"}, []interface{}{int64(7), "foo", 42, now})
+	}
+
+	t.Run("insert", func(t *testing.T) {
+		q := &captureQuerier{rows: returned()}
+		row := &writeRow{Name: "foo", Age: 42, writeAudit: writeAudit{CreatedAt: now}}
+		err := Insert(ctx, q, "users", row)
+		noError(t, err)
+		equal(t, `INSERT INTO "users" ("name", "age", "created_at") VALUES ($1, $2, $3) RETURNING "id", "name", "age", "created_at"`, q.sql)
+		equal(t, []interface{}{"foo", 42, now}, q.args)
+		equal(t, int64(7), row.ID)
+	})
+
+	t.Run("insert default values", func(t *testing.T) {
+		type generatedOnly struct {
+			ID int64 `db:"id,generated"`
+		}
+		q := &captureQuerier{rows: newFakeRows([]string{"id"}, []interface{}{int64(1)})}
+		err := Insert(ctx, q, "counters", &generatedOnly{})
+		noError(t, err)
+		equal(t, `INSERT INTO "counters" DEFAULT VALUES RETURNING "id"`, q.sql)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		q := &captureQuerier{rows: returned()}
+		row := &writeRow{ID: 7, Name: "foo", Age: 42}
+		err := Update(ctx, q, "users", row, "id = :id")
+		noError(t, err)
+		equal(t, `UPDATE "users" SET "name" = $2, "age" = $3 WHERE id = $1 RETURNING "id", "name", "age", "created_at"`, q.sql)
+		equal(t, []interface{}{int64(7), "foo", 42}, q.args)
+		equal(t, now, row.CreatedAt)
+	})
+
+	t.Run("update of several rows is rolled back", func(t *testing.T) {
+		db := &fakeDB{rows: newFakeRows([]string{"id"}, []interface{}{int64(1)}, []interface{}{int64(2)})}
+		err := Update(ctx, db, "users", &writeRow{ID: 1}, "name = :name")
+		equal(t, true, errors.Is(err, ErrMoreThanOneRow))
+		equal(t, 1, len(db.txs))
+		equal(t, true, db.txs[0].rolled)
+		equal(t, false, db.txs[0].committed)
+
+		tx := &fakeTx{rows: newFakeRows([]string{"id"}, []interface{}{int64(1)}, []interface{}{int64(2)})}
+		err = Update(ctx, tx, "users", &writeRow{ID: 1}, "name = :name")
+		equal(t, true, errors.Is(err, ErrMoreThanOneRow))
+		equal(t, true, tx.nested[0].rolled)
+		equal(t, false, tx.rolled)
+
+		tx = &fakeTx{rows: newFakeRows([]string{"id"}, []interface{}{int64(1)})}
+		err = Update(ctx, tx, "users", &writeRow{ID: 1}, "id = :id")
+		noError(t, err)
+		equal(t, true, tx.nested[0].committed)
+	})
+
+	t.Run("qualified table", func(t *testing.T) {
+		q := &captureQuerier{rows: returned()}
+		err := Insert(ctx, q, "public.users", &writeRow{Name: "foo"})
+		noError(t, err)
+		equal(t, `INSERT INTO "public"."users" ("name", "age", "created_at") VALUES ($1, $2, $3) RETURNING "id", "name", "age", "created_at"`, q.sql)
+	})
+
+	t.Run("upsert", func(t *testing.T) {
+		q := &captureQuerier{rows: returned()}
+		row := &writeRow{Name: "foo", Age: 42}
+		err := Upsert(ctx, q, "users", row, "name")
+		noError(t, err)
+		equal(t, `INSERT INTO "users" ("name", "age", "created_at") VALUES ($1, $2, $3) ON CONFLICT ("name") DO UPDATE SET "age" = EXCLUDED."age" RETURNING "id", "name", "age", "created_at"`, q.sql)
+	})
+
+	t.Run("upsert without updatable columns", func(t *testing.T) {
+		type key struct {
+			Name string `db:"name"`
+		}
+		q := &captureQuerier{rows: newFakeRows([]string{"name"}, []interface{}{"foo"})}
+		err := Upsert(ctx, q, "names", &key{Name: "foo"}, "name")
+		noError(t, err)
+		equal(t, `INSERT INTO "names" ("name") VALUES ($1) ON CONFLICT ("name") DO UPDATE SET "name" = EXCLUDED."name" RETURNING "name"`, q.sql)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		q := &captureQuerier{}
+		errorContains(t, Insert(ctx, q, "users", writeRow{}), "non nil pointer to a struct")
+		errorContains(t, Insert(ctx, q, "users", &struct{ ID int }{}), "has no db tags")
+		errorContains(t, Update(ctx, q, "users", &writeRow{}, ""), "where is empty")
+		errorContains(t, Update(ctx, q, "users", &writeRow{}, "id = :missing"), `named parameter "missing" not found`)
+		errorContains(t, Upsert(ctx, q, "users", &writeRow{}), "conflict columns are empty")
+	})
+}
+
+func TestWrite(t *testing.T) {
+	ctx := context.Background()
+	pool, err := pgxpool.Connect(ctx, connString)
+	noError(t, err)
+	defer pool.Close()
+
+	const createTable = `
+    CREATE TABLE if not exists easy_scan_write(
+    id bigserial primary key,
+    name text unique,
+    age int,
+    created_at timestamp)`
+
+	_, err = pool.Exec(ctx, createTable)
+	noError(t, err)
+	defer func() {
+		_, e := pool.Exec(ctx, "DROP TABLE easy_scan_write")
+		if e != nil {
+			panic(e)
+		}
+	}()
+
+	now := time.Now().Round(time.Minute).UTC()
+
+	row := &writeRow{Name: "foo", Age: 1, writeAudit: writeAudit{CreatedAt: now}}
+	err = Insert(ctx, pool, "easy_scan_write", row)
+	noError(t, err)
+	equal(t, int64(1), row.ID)
+
+	row.Age = 2
+	row.CreatedAt = now.Add(time.Hour)
+	err = Update(ctx, pool, "easy_scan_write", row, "id = :id")
+	noError(t, err)
+	equal(t, 2, row.Age)
+	equal(t, now, row.CreatedAt)
+
+	upserted := &writeRow{Name: "foo", Age: 3}
+	err = Upsert(ctx, pool, "easy_scan_write", upserted, "name")
+	noError(t, err)
+	equal(t, int64(1), upserted.ID)
+	equal(t, 3, upserted.Age)
+	equal(t, now, upserted.CreatedAt)
+
+	var count int
+	err = Get(ctx, pool, &count, "SELECT count(*) FROM easy_scan_write")
+	noError(t, err)
+	equal(t, 1, count)
+
+	err = Update(ctx, pool, "easy_scan_write", &writeRow{ID: 100}, "id = :id")
+	equal(t, pgx.ErrNoRows, err)
+}